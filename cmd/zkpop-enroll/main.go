@@ -0,0 +1,159 @@
+// Command zkpop-enroll runs a local enrollment server that issues X.509
+// certificates for KEM public keys, but only for keys that come with a
+// valid NIZKPoP. See package zkpop/enroll for the protocol.
+//
+// Without -ca-cert and -ca-key it creates a throwaway ECDSA CA and writes
+// its certificate to -ca-out, so clients can verify what it issues.
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"zkpop-go/zkpop"
+	"zkpop-go/zkpop/enroll"
+)
+
+func main() {
+	listen := flag.String("listen", "localhost:8443", "address to listen on")
+	caCertFile := flag.String("ca-cert", "", "PEM CA certificate")
+	caKeyFile := flag.String("ca-key", "", "PEM PKCS#8 CA private key (ECDSA, Ed25519 or RSA)")
+	caOut := flag.String("ca-out", "zkpop-enroll-ca.pem", "where to write the certificate of a generated CA")
+	algs := flag.String("algs", "", "comma-separated NIZKPoP schemes to accept (default: all)")
+	validity := flag.Duration("validity", enroll.DefaultValidity, "lifetime of issued certificates")
+	maxBody := flag.Int64("max-body", enroll.DefaultMaxBodyBytes, "maximum request body size in bytes")
+	rate := flag.Float64("rate", enroll.DefaultRate, "requests per second allowed per client")
+	burst := flag.Int("burst", enroll.DefaultBurst, "request burst allowed per client")
+	concurrent := flag.Int("max-concurrent", enroll.DefaultMaxConcurrent, "proofs verified at once")
+	flag.Parse()
+
+	if c := zkpop.Capabilities(); !c.Supported {
+		log.Fatalf("unsupported CPU: %v", c)
+	}
+
+	var caCert *x509.Certificate
+	var caKey crypto.Signer
+	var err error
+	if *caCertFile != "" || *caKeyFile != "" {
+		caCert, caKey, err = loadCA(*caCertFile, *caKeyFile)
+	} else {
+		caCert, caKey, err = newCA(*caOut)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := &enroll.Server{
+		CACert:        caCert,
+		CAKey:         caKey,
+		Validity:      *validity,
+		MaxBodyBytes:  *maxBody,
+		Rate:          *rate,
+		Burst:         *burst,
+		MaxConcurrent: *concurrent,
+	}
+	if *algs != "" {
+		s.Algorithms = strings.Split(*algs, ",")
+	}
+	if err := s.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+	}
+	log.Printf("enrollment server on http://%s%s, CA %q", *listen, enroll.Path, caCert.Subject.CommonName)
+	log.Fatal(srv.ListenAndServe())
+}
+
+func loadCA(certFile, keyFile string) (*x509.Certificate, crypto.Signer, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, nil, errors.New("-ca-cert and -ca-key must be given together")
+	}
+	certDER, err := readPEM(certFile, "CERTIFICATE")
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := readPEM(keyFile, "PRIVATE KEY")
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyDER)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: unsupported key type %T", keyFile, key)
+	}
+	return cert, signer, nil
+}
+
+func readPEM(file, blockType string) ([]byte, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s: no %s PEM block", file, blockType)
+	}
+	return block.Bytes, nil
+}
+
+// newCA creates a self-signed ECDSA P-256 CA valid for a year and writes
+// its certificate to out.
+func newCA(out string) (*x509.Certificate, crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "zkpop-enroll local CA"},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("generated a throwaway CA, certificate written to %s", out)
+	return cert, key, nil
+}
@@ -0,0 +1,151 @@
+// Command zkpop-kat regenerates the NIST Known Answer Tests of every Kyber
+// and FrodoKEM parameter set linked into package zkpop and compares them
+// with the upstream responses.
+//
+// For each scheme it writes <out>/<scheme>/PQCkemKAT_<sk bytes>.req and
+// .rsp. If <upstream>/<scheme>/PQCkemKAT_<sk bytes>.rsp exists, every test
+// is compared with it and the command fails on the first difference. The
+// upstream files come from the KAT directories of the round-3 submission
+// packages, or from PQCgenKAT_kem built in the KEM-NIZKPoP submodule.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"zkpop-go/zkpop"
+	"zkpop-go/zkpop/kat"
+)
+
+var schemes = []zkpop.KEM{
+	zkpop.Kyber512,
+	zkpop.Kyber768,
+	zkpop.Kyber1024,
+	zkpop.Kyber512_90s,
+	zkpop.Kyber768_90s,
+	zkpop.Kyber1024_90s,
+	zkpop.Frodo640,
+	zkpop.Frodo976,
+	zkpop.Frodo1344,
+	zkpop.Frodo640AES,
+	zkpop.Frodo640SHAKE,
+}
+
+func main() {
+	out := flag.String("out", "kat", "directory to write the .req and .rsp files to")
+	upstream := flag.String("upstream", "", "directory holding <scheme>/PQCkemKAT_<sk bytes>.rsp to compare with")
+	count := flag.Int("n", kat.DefaultCount, "number of tests per scheme")
+	only := flag.String("schemes", "", "comma-separated schemes to run (default: all)")
+	flag.Parse()
+
+	if c := zkpop.Capabilities(); !c.Supported {
+		log.Fatalf("unsupported CPU: %v", c)
+	}
+
+	failed := false
+	seeds := kat.Seeds(*count)
+	for _, k := range schemes {
+		if *only != "" && !selected(k.Name(), *only) {
+			continue
+		}
+		status, err := run(k, seeds, *out, *upstream)
+		if err != nil {
+			fmt.Printf("%-20s FAIL %v\n", k.Name(), err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%-20s ok   %s\n", k.Name(), status)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func selected(name, list string) bool {
+	for _, s := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(s), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func run(k zkpop.KEM, seeds [][]byte, out, upstream string) (string, error) {
+	dir := filepath.Join(out, k.Name())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	base := filepath.Join(dir, kat.FileName(k))
+
+	req, err := os.Create(base + ".req")
+	if err != nil {
+		return "", err
+	}
+	err = kat.WriteRequest(req, seeds)
+	if cerr := req.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var want []*kat.Entry
+	status := "no upstream response to compare with"
+	if upstream != "" {
+		want, err = readResponse(filepath.Join(upstream, k.Name(), kat.FileName(k)+".rsp"))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return "", err
+		case len(want) < len(seeds):
+			return "", fmt.Errorf("upstream response has %d tests, want at least %d", len(want), len(seeds))
+		default:
+			status = "matches upstream"
+		}
+	}
+
+	rsp, err := os.Create(base + ".rsp")
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Close()
+	w := bufio.NewWriter(rsp)
+	if err := kat.WriteResponseHeader(w, k.Name()); err != nil {
+		return "", err
+	}
+	for i, seed := range seeds {
+		e, err := kat.Generate(k, i, seed)
+		if err != nil {
+			return "", err
+		}
+		if err := kat.WriteEntry(w, e); err != nil {
+			return "", err
+		}
+		if want != nil {
+			if err := kat.Compare(e, want[i]); err != nil {
+				w.Flush()
+				return "", err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d tests, %s", len(seeds), status), rsp.Close()
+}
+
+func readResponse(name string) ([]*kat.Entry, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return kat.Read(f)
+}
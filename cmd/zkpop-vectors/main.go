@@ -0,0 +1,115 @@
+// Command zkpop-vectors writes and checks golden NIZKPoP test vectors, one
+// JSON file per scheme; see package zkpop/vectors for what they contain.
+//
+//	zkpop-vectors gen [-dir testdata/nizkpop] [-n 2] [-schemes ...]
+//	zkpop-vectors check [-dir testdata/nizkpop] [-schemes ...]
+//
+// check fails if any honest vector is not reproduced from its seed or is
+// rejected, or if any corrupted vector is accepted. Schemes without a file
+// in -dir are skipped.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"zkpop-go/zkpop"
+	"zkpop-go/zkpop/vectors"
+)
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "gen" && os.Args[1] != "check") {
+		fmt.Fprintln(os.Stderr, "usage: zkpop-vectors gen|check [flags]")
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+	fl := flag.NewFlagSet(cmd, flag.ExitOnError)
+	dir := fl.String("dir", filepath.Join("testdata", "nizkpop"), "directory of the <scheme>.json files")
+	n := fl.Int("n", 2, "honest vectors per scheme (gen only)")
+	only := fl.String("schemes", "", "comma-separated schemes (default: all NIZKPoP schemes)")
+	fl.Parse(os.Args[2:])
+
+	if c := zkpop.Capabilities(); !c.Supported {
+		log.Fatalf("unsupported CPU: %v", c)
+	}
+
+	failed := false
+	for _, k := range zkpop.All() {
+		p, ok := k.(zkpop.ProvingKEM)
+		if !ok || (*only != "" && !selected(p.Name(), *only)) {
+			continue
+		}
+		file := filepath.Join(*dir, p.Name()+".json")
+		var err error
+		if cmd == "gen" {
+			err = gen(p, *n, file)
+		} else {
+			err = check(p, file)
+		}
+		if err != nil {
+			fmt.Printf("%-28s FAIL %v\n", p.Name(), err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func selected(name, list string) bool {
+	for _, s := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(s), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func gen(p zkpop.ProvingKEM, n int, file string) error {
+	s, err := vectors.Generate(p, n)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("%-28s wrote %d valid and %d invalid vectors\n", p.Name(), len(s.Valid), len(s.Invalid))
+	return nil
+}
+
+func check(p zkpop.ProvingKEM, file string) error {
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Printf("%-28s skip (no %s)\n", p.Name(), file)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var s vectors.Set
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if errs := vectors.Check(p, &s); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Printf("%-28s %v\n", p.Name(), err)
+		}
+		return fmt.Errorf("%d of %d vectors failed", len(errs), len(s.Valid)+len(s.Invalid))
+	}
+	fmt.Printf("%-28s ok   %d valid, %d invalid\n", p.Name(), len(s.Valid), len(s.Invalid))
+	return nil
+}
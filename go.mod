@@ -1,3 +1,3 @@
 module zkpop-go
 
-go 1.22.2
+go 1.24
@@ -1,12 +1,6 @@
 // main.go
 package main
 
-/*
-#cgo CFLAGS: -I/home/andrei/zkpop-go/external/KEM-NIZKPoP/frodo-zkpop/frodo640/ -I/home/andrei/zkpop-go/external/KEM-NIZKPoP/kyber-zkpop/avx2/ -I/usr/include/
-#cgo LDFLAGS: -L/home/andrei/zkpop-go/external/KEM-NIZKPoP/frodo-zkpop/frodo640/ -L/home/andrei/zkpop-go/external/KEM-NIZKPoP/kyber-zkpop/avx2/ -L/usr/lib/ -lfrodo -lpqcrystals_kyber512_avx2 -lpqcrystals_kyber768_avx2 -lpqcrystals_kyber1024_avx2 -lpqcrystals_aes256ctr_avx2 -lpqcrystals_fips202_ref -lpqcrystals_fips202x4_avx2 -lssl -lcrypto
-*/
-import "C"
-
 import (
 	"bytes"
 	"fmt"
@@ -397,6 +391,11 @@ func testKyber(N int) {
 
 func main() {
 	N := 1000
+	caps := zkpop.Capabilities()
+	fmt.Println("CPU:", caps)
+	if !caps.Supported {
+		log.Fatal(zkpop.ErrUnsupportedCPU)
+	}
 	fmt.Printf("Testing %d iterations for each algorithm...\n", N)
 
 	//Kyber
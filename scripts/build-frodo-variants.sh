@@ -0,0 +1,56 @@
+#!/bin/sh
+# Builds FrodoKEM-640 twice from the KEM-NIZKPoP submodule, once with the
+# AES128 and once with the SHAKE128 matrix generation, and renames the API
+# of each build so both can be linked into the same binary:
+#
+#   crypto_kem_keypair_Frodo640 -> crypto_kem_keypair_Frodo640_AES / _SHAKE
+#
+# Every other symbol is made local, so the two archives do not clash with
+# each other or with frodo640/libfrodo.a. The object defining randombytes is
+# left out, so that, like every other library, they draw from the one in
+# zkpop/rng.c. The results are written to
+# build/frodo/libfrodo640_aes.a and build/frodo/libfrodo640_shake.a, where
+# zkpop/frodo640aes.go and zkpop/frodo640shake.go expect them.
+#
+# Extra make variables (e.g. OPT_LEVEL=REFERENCE) are passed through.
+set -eu
+
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+OUT="$ROOT/build/frodo"
+mkdir -p "$OUT"
+cd "$ROOT/external/KEM-NIZKPoP/frodo-zkpop"
+
+API="crypto_kem_keypair crypto_kem_enc crypto_kem_dec crypto_kem_keypair_nizkpop crypto_nizkpop_verify"
+
+for gen in AES128 SHAKE128; do
+	case $gen in
+	AES128) suffix=AES; lib=libfrodo640_aes.a; openssl=TRUE ;;
+	SHAKE128) suffix=SHAKE; lib=libfrodo640_shake.a; openssl=FALSE ;;
+	esac
+
+	make clean >/dev/null
+	make lib640 OPT_LEVEL=FAST USE_OPENSSL=$openssl GENERATION_A=$gen \
+		ZKPOP_N=65536 ZKPOP_TAU=8 "$@"
+
+	tmp=$(mktemp -d)
+	: >"$tmp/keep"
+	: >"$tmp/rename"
+	for f in $API; do
+		echo "${f}_Frodo640_$suffix" >>"$tmp/keep"
+		echo "${f}_Frodo640 ${f}_Frodo640_$suffix" >>"$tmp/rename"
+	done
+
+	cp frodo640/libfrodo.a "$tmp/libfrodo.a"
+	rng=$(nm -A --defined-only "$tmp/libfrodo.a" | sed -n 's/^[^:]*:\([^:]*\):.* T randombytes$/\1/p')
+	[ -z "$rng" ] || ar d "$tmp/libfrodo.a" "$rng"
+	ld -r --whole-archive "$tmp/libfrodo.a" -o "$tmp/frodo640.o"
+	objcopy --redefine-syms="$tmp/rename" "$tmp/frodo640.o"
+	objcopy --keep-global-symbols="$tmp/keep" "$tmp/frodo640.o"
+	rm -f "$OUT/$lib"
+	ar rcs "$OUT/$lib" "$tmp/frodo640.o"
+	rm -rf "$tmp"
+done
+
+# Leave the default frodo640/libfrodo.a as the README builds it.
+make clean >/dev/null
+make OPT_LEVEL=FAST USE_OPENSSL=FALSE GENERATION_A=SHAKE128 ZKPOP_N=65536 ZKPOP_TAU=8 "$@"
@@ -0,0 +1,81 @@
+#!/bin/sh
+# Builds the extra Kyber NIZKPoP profiles used with -tags zkpop_profiles.
+#
+# Each profile is kyber-zkpop compiled with its own ZKPOP_N and ZKPOP_TAU.
+# The API of every build is renamed to carry the profile,
+#
+#   pqcrystals_kyber512_avx2_crypto_kem_keypair_nizkpop
+#     -> pqcrystals_kyber512_avx2_n16t32_crypto_kem_keypair_nizkpop
+#
+# and every other symbol it defines is made local, so the builds link next to
+# each other and next to the default shared libraries (which still provide
+# fips202 and aes256ctr; randombytes comes from zkpop/rng.c). All profiles of
+# a backend are collected in build/kyber/<backend>/libkyber_profiles.a, where
+# zkpop/profiles_avx2.go and zkpop/profiles_ref.go expect them.
+#
+# Usage: scripts/build-kyber-profiles.sh [avx2|ref]   (default avx2)
+set -eu
+
+BACKEND=${1:-avx2}
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+OUT="$ROOT/build/kyber/$BACKEND"
+SRC="$ROOT/external/KEM-NIZKPoP/kyber-zkpop/$BACKEND"
+
+# level ZKPOP_N ZKPOP_TAU. Keep in sync with KYBER_PROFILES in
+# zkpop/profiles_kyber.go.
+PROFILES="
+512 4 64
+512 16 32
+512 256 16
+768 16 48
+768 256 24
+1024 16 64
+1024 256 32
+"
+
+# Ask the submodule's Makefile for its compiler flags and sources, so the
+# profiles are compiled exactly like the default libraries.
+makevar() {
+	printf 'print-%%:\n\t@echo $($*)\n' | make -s -C "$SRC" -f Makefile -f - "print-$1"
+}
+CC=${CC:-$(makevar CC)}
+CFLAGS=$(makevar CFLAGS)
+SOURCES="$(makevar SOURCES) symmetric-shake.c zkpop.c"
+
+mkdir -p "$OUT"
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+echo "$PROFILES" | while read -r level n tau; do
+	[ -n "$level" ] || continue
+	case $level in
+	512) k=2 ;;
+	768) k=3 ;;
+	1024) k=4 ;;
+	esac
+	ns=pqcrystals_kyber${level}_${BACKEND}
+	tag=n${n}t${tau}
+
+	mkdir "$tmp/$tag-$level"
+	(
+		cd "$tmp/$tag-$level"
+		for f in $SOURCES; do
+			# shellcheck disable=SC2086
+			$CC $CFLAGS -fPIC -DKYBER_K=$k -DZKPOP_N=$n -DZKPOP_TAU=$tau \
+				-I"$SRC" -c "$SRC/$f" -o "$(basename "$f").o"
+		done
+		ld -r ./*.o -o profile.o
+	)
+
+	: >"$tmp/keep"
+	: >"$tmp/rename"
+	for f in crypto_kem_keypair_nizkpop crypto_nizkpop_verify; do
+		echo "${ns}_${tag}_$f" >>"$tmp/keep"
+		echo "${ns}_$f ${ns}_${tag}_$f" >>"$tmp/rename"
+	done
+	objcopy --redefine-syms="$tmp/rename" "$tmp/$tag-$level/profile.o"
+	objcopy --keep-global-symbols="$tmp/keep" "$tmp/$tag-$level/profile.o" "$tmp/kyber${level}_$tag.o"
+done
+
+rm -f "$OUT/libkyber_profiles.a"
+ar rcs "$OUT/libkyber_profiles.a" "$tmp"/kyber*_n*t*.o
@@ -0,0 +1,30 @@
+/********************************************************************************************
+* FrodoKEM: Learning with Errors Key Encapsulation
+*
+* Abstract: parameters and API for FrodoKEM-640 with SHAKE128 generation of A
+* Symbols renamed with a _SHAKE suffix by scripts/build-frodo-variants.sh
+* https://github.com/Chair-for-Security-Engineering/KEM-NIZKPoP/blob/764abb34a7e1128c6f60114a7d2f9a6dd65fe3ce/frodo-zkpop/src/api_frodo640.h 
+*********************************************************************************************/
+
+#ifndef _API_Frodo640_SHAKE_H_
+#define _API_Frodo640_SHAKE_H_
+
+
+#define CRYPTO_SECRETKEYBYTES  19888     // sizeof(s) + CRYPTO_PUBLICKEYBYTES + 2*PARAMS_N*PARAMS_NBAR + BYTES_PKHASH
+#define CRYPTO_PUBLICKEYBYTES   9616     // sizeof(seed_A) + (PARAMS_LOGQ*PARAMS_N*PARAMS_NBAR)/8
+#define CRYPTO_BYTES              16
+#define CRYPTO_CIPHERTEXTBYTES  9720     // (PARAMS_LOGQ*PARAMS_N*PARAMS_NBAR)/8 + (PARAMS_LOGQ*PARAMS_NBAR*PARAMS_NBAR)/8
+
+// Algorithm name
+#define CRYPTO_ALGNAME "FrodoKEM-640-SHAKE"
+
+
+int crypto_kem_keypair_Frodo640_SHAKE(unsigned char *pk, unsigned char *sk);
+int crypto_kem_enc_Frodo640_SHAKE(unsigned char *ct, unsigned char *ss, const unsigned char *pk);
+int crypto_kem_dec_Frodo640_SHAKE(unsigned char *ss, const unsigned char *ct, const unsigned char *sk);
+
+int crypto_kem_keypair_nizkpop_Frodo640_SHAKE(unsigned char* pk, unsigned char* sk, unsigned char **zkpop, unsigned long *zkpop_size);
+int crypto_nizkpop_verify_Frodo640_SHAKE(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size);
+
+
+#endif
@@ -0,0 +1,29 @@
+/********************************************************************************************
+* FrodoKEM: Learning with Errors Key Encapsulation
+*
+* Abstract: parameters and API for FrodoKEM-976
+* https://github.com/Chair-for-Security-Engineering/KEM-NIZKPoP/blob/764abb34a7e1128c6f60114a7d2f9a6dd65fe3ce/frodo-zkpop/src/api_frodo976.h 
+*********************************************************************************************/
+
+#ifndef _API_Frodo976_H_
+#define _API_Frodo976_H_
+
+
+#define CRYPTO_SECRETKEYBYTES  31296    // sizeof(s) + CRYPTO_PUBLICKEYBYTES + 2*PARAMS_N*PARAMS_NBAR + BYTES_PKHASH
+#define CRYPTO_PUBLICKEYBYTES  15632     // sizeof(seed_A) + (PARAMS_LOGQ*PARAMS_N*PARAMS_NBAR)/8
+#define CRYPTO_BYTES              24
+#define CRYPTO_CIPHERTEXTBYTES 15744     // (PARAMS_LOGQ*PARAMS_N*PARAMS_NBAR)/8 + (PARAMS_LOGQ*PARAMS_NBAR*PARAMS_NBAR)/8
+
+// Algorithm name
+#define CRYPTO_ALGNAME "FrodoKEM-976"
+
+
+int crypto_kem_keypair_Frodo976(unsigned char *pk, unsigned char *sk);
+int crypto_kem_enc_Frodo976(unsigned char *ct, unsigned char *ss, const unsigned char *pk);
+int crypto_kem_dec_Frodo976(unsigned char *ss, const unsigned char *ct, const unsigned char *sk);
+
+int crypto_kem_keypair_nizkpop_Frodo976(unsigned char* pk, unsigned char* sk, unsigned char **zkpop, unsigned long *zkpop_size);
+int crypto_nizkpop_verify_Frodo976(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size);
+
+
+#endif
@@ -0,0 +1,23 @@
+//go:build !zkpop_ref
+
+package zkpop
+
+// The default backend links the AVX2 builds of kyber-zkpop, which require a
+// CPU with AVX2, AES-NI and BMI2. Build with -tags zkpop_ref to link the
+// portable reference implementation instead.
+//
+// The libraries are built in place in the KEM-NIZKPoP submodule and located
+// through an rpath, so they need not be installed system-wide.
+
+/*
+#cgo LDFLAGS: -L${SRCDIR}/../external/KEM-NIZKPoP/kyber-zkpop/avx2 -Wl,-rpath,${SRCDIR}/../external/KEM-NIZKPoP/kyber-zkpop/avx2
+#cgo LDFLAGS: -lpqcrystals_kyber512_avx2 -lpqcrystals_kyber768_avx2 -lpqcrystals_kyber1024_avx2
+#cgo LDFLAGS: -lpqcrystals_kyber512_90s_avx2 -lpqcrystals_kyber768_90s_avx2 -lpqcrystals_kyber1024_90s_avx2
+#cgo LDFLAGS: -lpqcrystals_aes256ctr_avx2 -lpqcrystals_sha2_ref -lpqcrystals_fips202_ref -lpqcrystals_fips202x4_avx2
+*/
+import "C"
+
+// Backend names the Kyber implementation this package is linked against.
+const Backend = "avx2"
+
+const backendNeedsAVX2 = true
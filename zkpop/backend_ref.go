@@ -0,0 +1,22 @@
+//go:build zkpop_ref
+
+package zkpop
+
+// The reference backend links the portable C builds of kyber-zkpop. It runs
+// on any CPU, including non-x86 hosts, at the cost of speed. Build the
+// libraries with `make shared` in kyber-zkpop/ref, patched the same way as
+// the avx2 Makefile so that zkpop.c is part of each parameter set.
+
+/*
+#cgo CFLAGS: -DZKPOP_REF
+#cgo LDFLAGS: -L${SRCDIR}/../external/KEM-NIZKPoP/kyber-zkpop/ref -Wl,-rpath,${SRCDIR}/../external/KEM-NIZKPoP/kyber-zkpop/ref
+#cgo LDFLAGS: -lpqcrystals_kyber512_ref -lpqcrystals_kyber768_ref -lpqcrystals_kyber1024_ref
+#cgo LDFLAGS: -lpqcrystals_kyber512_90s_ref -lpqcrystals_kyber768_90s_ref -lpqcrystals_kyber1024_90s_ref
+#cgo LDFLAGS: -lpqcrystals_aes256ctr_ref -lpqcrystals_sha2_ref -lpqcrystals_fips202_ref
+*/
+import "C"
+
+// Backend names the Kyber implementation this package is linked against.
+const Backend = "ref"
+
+const backendNeedsAVX2 = false
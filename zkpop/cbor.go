@@ -0,0 +1,189 @@
+package zkpop
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// A minimal CBOR (RFC 8949) codec for the COSE encodings in cose.go. It
+// writes, and only accepts, the deterministic encoding of section 4.2.1:
+// definite lengths, arguments in their shortest form, and map keys in
+// bytewise lexicographic order of their encodings, without duplicates.
+
+var (
+	// ErrMalformedCBOR is returned for CBOR input that cannot be decoded or
+	// does not have the expected structure.
+	ErrMalformedCBOR = errors.New("malformed CBOR")
+	// ErrNonCanonicalCBOR is returned when CBOR input is well formed but not
+	// in deterministic encoding.
+	ErrNonCanonicalCBOR = errors.New("non-canonical CBOR")
+)
+
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+)
+
+func cborAppendHead(b []byte, major byte, n uint64) []byte {
+	m := major << 5
+	switch {
+	case n < 24:
+		return append(b, m|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, m|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, m|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, m|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, m|27), n)
+}
+
+func cborAppendInt(b []byte, n int64) []byte {
+	if n < 0 {
+		return cborAppendHead(b, cborNegInt, uint64(-1-n))
+	}
+	return cborAppendHead(b, cborUint, uint64(n))
+}
+
+func cborAppendBytes(b, v []byte) []byte {
+	return append(cborAppendHead(b, cborBytes, uint64(len(v))), v...)
+}
+
+func cborAppendText(b []byte, v string) []byte {
+	return append(cborAppendHead(b, cborText, uint64(len(v))), v...)
+}
+
+type cborDecoder struct {
+	b []byte
+}
+
+func (d *cborDecoder) head() (major byte, n uint64, err error) {
+	if len(d.b) == 0 {
+		return 0, 0, fmt.Errorf("%w: unexpected end of input", ErrMalformedCBOR)
+	}
+	major, ai := d.b[0]>>5, d.b[0]&0x1f
+	d.b = d.b[1:]
+	if ai < 24 {
+		return major, uint64(ai), nil
+	}
+	if ai > 27 {
+		// 28-30 are reserved and 31 is an indefinite length or break.
+		return 0, 0, fmt.Errorf("%w: indefinite length or reserved value", ErrNonCanonicalCBOR)
+	}
+	size := 1 << (ai - 24)
+	if len(d.b) < size {
+		return 0, 0, fmt.Errorf("%w: unexpected end of input", ErrMalformedCBOR)
+	}
+	switch size {
+	case 1:
+		n = uint64(d.b[0])
+	case 2:
+		n = uint64(binary.BigEndian.Uint16(d.b))
+	case 4:
+		n = uint64(binary.BigEndian.Uint32(d.b))
+	case 8:
+		n = binary.BigEndian.Uint64(d.b)
+	}
+	d.b = d.b[size:]
+	if len(cborAppendHead(nil, major, n)) != 1+size {
+		return 0, 0, fmt.Errorf("%w: argument not in shortest form", ErrNonCanonicalCBOR)
+	}
+	return major, n, nil
+}
+
+func (d *cborDecoder) expect(major byte) (uint64, error) {
+	m, n, err := d.head()
+	if err != nil {
+		return 0, err
+	}
+	if m != major {
+		return 0, fmt.Errorf("%w: major type %d, want %d", ErrMalformedCBOR, m, major)
+	}
+	return n, nil
+}
+
+func (d *cborDecoder) uint() (uint64, error) {
+	return d.expect(cborUint)
+}
+
+func (d *cborDecoder) int() (int64, error) {
+	m, n, err := d.head()
+	if err != nil {
+		return 0, err
+	}
+	if (m != cborUint && m != cborNegInt) || n > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: want an integer", ErrMalformedCBOR)
+	}
+	if m == cborNegInt {
+		return -1 - int64(n), nil
+	}
+	return int64(n), nil
+}
+
+// bytes reads a byte string of at most max bytes. The length is checked
+// before anything is copied.
+func (d *cborDecoder) bytes(max int) ([]byte, error) {
+	n, err := d.expect(cborBytes)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(max) {
+		return nil, fmt.Errorf("%w: byte string of %d bytes exceeds %d", ErrMalformedCBOR, n, max)
+	}
+	if uint64(len(d.b)) < n {
+		return nil, fmt.Errorf("%w: unexpected end of input", ErrMalformedCBOR)
+	}
+	v := bytes.Clone(d.b[:n])
+	d.b = d.b[n:]
+	return v, nil
+}
+
+func (d *cborDecoder) text(max int) (string, error) {
+	n, err := d.expect(cborText)
+	if err != nil {
+		return "", err
+	}
+	if n > uint64(max) || uint64(len(d.b)) < n {
+		return "", fmt.Errorf("%w: bad text string length %d", ErrMalformedCBOR, n)
+	}
+	v := string(d.b[:n])
+	d.b = d.b[n:]
+	return v, nil
+}
+
+// mapKeys reads a map header and returns a function that reads the next
+// integer key, enforcing canonical key order.
+func (d *cborDecoder) mapKeys() (n uint64, next func() (int64, error), err error) {
+	if n, err = d.expect(cborMap); err != nil {
+		return 0, nil, err
+	}
+	var prev []byte
+	return n, func() (int64, error) {
+		start := d.b
+		k, err := d.int()
+		if err != nil {
+			return 0, err
+		}
+		enc := start[:len(start)-len(d.b)]
+		if prev != nil && bytes.Compare(prev, enc) >= 0 {
+			return 0, fmt.Errorf("%w: map keys out of order or duplicated", ErrNonCanonicalCBOR)
+		}
+		prev = enc
+		return k, nil
+	}, nil
+}
+
+func (d *cborDecoder) end() error {
+	if len(d.b) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrMalformedCBOR, len(d.b))
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package zkpop
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// COSE_Key (RFC 9052) encoding of public keys, using the "AKP" key type:
+//
+//	{ 1 (kty): 7 (AKP), 3 (alg): "Kyber768", -1 (pub): h'...' }
+//
+// alg is the name of the plain KEM as a text string, as in the JWK
+// encoding. A key identifier (2, kid) is accepted but not kept.
+//
+// Envelopes are encoded as a CBOR array mirroring the binary format:
+//
+//	[ scheme: uint, profile: uint, N: uint, TAU: uint,
+//	  pkHash: bstr .size 32, proof: bstr ]
+//
+// Both decoders only accept deterministically encoded CBOR, and check every
+// length against the scheme before reading: public keys must have the exact
+// size, proofs at most the profile's maximum (KYBER_ZKPOP_MAXBYTES for
+// Kyber).
+
+const (
+	coseKeyKty = 1
+	coseKeyKid = 2
+	coseKeyAlg = 3
+	coseKeyPub = -1
+
+	coseKtyAKP = 7
+
+	// coseMaxAlgLen and coseMaxKidLen bound the text and byte strings of a
+	// COSE_Key that are not key material.
+	coseMaxAlgLen = 64
+	coseMaxKidLen = 64
+
+	// maxUnboundedProofSize caps proofs of schemes whose C code exports no
+	// maximum size (FrodoKEM).
+	maxUnboundedProofSize = 32 << 20
+)
+
+// MarshalCOSEKey encodes pk as a COSE_Key.
+func MarshalCOSEKey(pk *PublicKey) ([]byte, error) {
+	b := cborAppendHead(nil, cborMap, 3)
+	b = cborAppendInt(b, coseKeyKty)
+	b = cborAppendInt(b, coseKtyAKP)
+	b = cborAppendInt(b, coseKeyAlg)
+	b = cborAppendText(b, keyScheme(pk.scheme).Name())
+	b = cborAppendInt(b, coseKeyPub)
+	return cborAppendBytes(b, pk.b), nil
+}
+
+// ParseCOSEKey decodes a COSE_Key public key.
+func ParseCOSEKey(data []byte) (*PublicKey, error) {
+	d := &cborDecoder{data}
+	n, next, err := d.mapKeys()
+	if err != nil {
+		return nil, err
+	}
+	var scheme KEM
+	var pub []byte
+	var kty bool
+	for range n {
+		label, err := next()
+		if err != nil {
+			return nil, err
+		}
+		switch label {
+		case coseKeyKty:
+			v, err := d.int()
+			if err != nil {
+				return nil, err
+			}
+			if v != coseKtyAKP {
+				return nil, fmt.Errorf("unsupported COSE_Key kty %d", v)
+			}
+			kty = true
+		case coseKeyKid:
+			if _, err := d.bytes(coseMaxKidLen); err != nil {
+				return nil, err
+			}
+		case coseKeyAlg:
+			alg, err := d.text(coseMaxAlgLen)
+			if err != nil {
+				return nil, err
+			}
+			if scheme, err = Lookup(alg); err != nil {
+				return nil, err
+			}
+			if _, ok := scheme.(ProvingKEM); ok || scheme.Name() != alg {
+				return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, alg)
+			}
+		case coseKeyPub:
+			// Canonical order puts alg (3) before pub (-1), so the size
+			// of the key is known here.
+			if scheme == nil {
+				return nil, fmt.Errorf("%w: COSE_Key pub before alg", ErrMalformedCBOR)
+			}
+			if pub, err = d.bytes(scheme.PublicKeySize()); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported COSE_Key label %d", label)
+		}
+	}
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+	if !kty || scheme == nil || pub == nil {
+		return nil, fmt.Errorf("%w: COSE_Key lacks kty, alg or pub", ErrMalformedCBOR)
+	}
+	return NewPublicKey(scheme, pub)
+}
+
+// MarshalCBOR encodes e as a CBOR array.
+func (e *Envelope) MarshalCBOR() ([]byte, error) {
+	id, err := proofSchemeID(e.scheme)
+	if err != nil {
+		return nil, err
+	}
+	b := cborAppendHead(nil, cborArray, 6)
+	b = cborAppendHead(b, cborUint, uint64(id))
+	b = cborAppendHead(b, cborUint, uint64(e.profile.ID))
+	b = cborAppendHead(b, cborUint, uint64(e.profile.N))
+	b = cborAppendHead(b, cborUint, uint64(e.profile.Tau))
+	b = cborAppendBytes(b, e.pkHash[:])
+	return cborAppendBytes(b, e.body), nil
+}
+
+// UnmarshalCBOR decodes an envelope produced by MarshalCBOR.
+func (e *Envelope) UnmarshalCBOR(data []byte) error {
+	d := &cborDecoder{data}
+	if n, err := d.expect(cborArray); err != nil {
+		return err
+	} else if n != 6 {
+		return fmt.Errorf("%w: envelope array has %d elements, want 6", ErrMalformedCBOR, n)
+	}
+	var f [4]uint64
+	for i := range f {
+		v, err := d.uint()
+		if err != nil {
+			return err
+		}
+		f[i] = v
+	}
+	if f[0] > 0xffff || f[1] > 0xff {
+		return fmt.Errorf("%w: scheme or profile out of range", ErrMalformedEnvelope)
+	}
+	scheme, err := proofSchemeByID(uint16(f[0]))
+	if err != nil {
+		return err
+	}
+	limit := maxProofSize(scheme, uint8(f[1]))
+	if limit == 0 {
+		return fmt.Errorf("%w: %s has no profile %d", ErrUnknownProfile, scheme.Name(), f[1])
+	}
+	pkHash, err := d.bytes(sha256.Size)
+	if err != nil {
+		return err
+	}
+	body, err := d.bytes(limit)
+	if err != nil {
+		return err
+	}
+	if err := d.end(); err != nil {
+		return err
+	}
+	dec, err := decodedEnvelope(scheme, uint8(f[1]), f[2], f[3], pkHash, body)
+	if err != nil {
+		return err
+	}
+	*e = *dec
+	return nil
+}
+
+// maxProofSize returns the largest proof scheme can produce with the given
+// profile, or 0 if it has no such profile.
+func maxProofSize(scheme ProvingKEM, id uint8) int {
+	s, ok := scheme.(*provingScheme)
+	if !ok {
+		return maxUnboundedProofSize
+	}
+	p := s.profile(id)
+	switch {
+	case p == nil:
+		return 0
+	case p.limits.max == 0:
+		return maxUnboundedProofSize
+	}
+	return p.limits.max
+}
@@ -0,0 +1,47 @@
+package zkpop
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedCPU is returned by every binding when the linked backend
+// needs instructions this CPU does not provide. Calling into the C code
+// anyway would kill the process with SIGILL.
+var ErrUnsupportedCPU = errors.New("CPU lacks AVX2, AES-NI or BMI2 required by the avx2 backend; rebuild with -tags zkpop_ref")
+
+// CapabilityReport describes the CPU features relevant to this package and
+// whether the linked backend can run on them.
+type CapabilityReport struct {
+	Backend   string
+	AVX2      bool
+	AES       bool
+	BMI2      bool
+	Supported bool
+}
+
+func (c CapabilityReport) String() string {
+	return fmt.Sprintf("backend=%s avx2=%t aes=%t bmi2=%t supported=%t",
+		c.Backend, c.AVX2, c.AES, c.BMI2, c.Supported)
+}
+
+var capabilities CapabilityReport
+
+func init() {
+	capabilities = detectCPU()
+	capabilities.Backend = Backend
+	capabilities.Supported = !backendNeedsAVX2 ||
+		(capabilities.AVX2 && capabilities.AES && capabilities.BMI2)
+}
+
+// Capabilities reports the CPU features detected at init and whether the
+// bindings are usable on this machine.
+func Capabilities() CapabilityReport { return capabilities }
+
+// checkCPU guards every call into the C libraries.
+func checkCPU() error {
+	if !capabilities.Supported {
+		return ErrUnsupportedCPU
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+//go:build !386 && !amd64
+
+package zkpop
+
+// detectCPU reports no x86 extensions on other architectures.
+func detectCPU() CapabilityReport { return CapabilityReport{} }
@@ -0,0 +1,18 @@
+//go:build 386 || amd64
+
+package zkpop
+
+/*
+static int zkpop_cpu_avx2(void) { __builtin_cpu_init(); return __builtin_cpu_supports("avx2"); }
+static int zkpop_cpu_aes(void)  { __builtin_cpu_init(); return __builtin_cpu_supports("aes"); }
+static int zkpop_cpu_bmi2(void) { __builtin_cpu_init(); return __builtin_cpu_supports("bmi2"); }
+*/
+import "C"
+
+func detectCPU() CapabilityReport {
+	return CapabilityReport{
+		AVX2: C.zkpop_cpu_avx2() != 0,
+		AES:  C.zkpop_cpu_aes() != 0,
+		BMI2: C.zkpop_cpu_bmi2() != 0,
+	}
+}
@@ -0,0 +1,199 @@
+package csr
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"zkpop-go/zkpop"
+)
+
+var (
+	oidExtensionKeyUsage        = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionBasicConstraint = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidExtensionSubjectKeyID    = asn1.ObjectIdentifier{2, 5, 29, 14}
+	oidExtensionAuthorityKeyID  = asn1.ObjectIdentifier{2, 5, 29, 35}
+
+	oidSignatureECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidSignatureECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidSignatureECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidSignatureSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSignatureEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+type certificate struct {
+	TBS                asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+}
+
+type tbsCertificate struct {
+	Version            int `asn1:"explicit,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           validity
+	Subject            asn1.RawValue
+	PublicKey          asn1.RawValue
+	Extensions         []pkix.Extension `asn1:"explicit,tag:3"`
+}
+
+type validity struct {
+	NotBefore, NotAfter time.Time
+}
+
+type authorityKeyID struct {
+	ID []byte `asn1:"tag:0"`
+}
+
+// Issue verifies the NIZKPoP in req and, if it is accepted, returns a DER
+// certificate for req.PublicKey issued by parent and signed with priv.
+//
+// The subject and DNS names are taken from req; template supplies
+// SerialNumber, NotBefore and NotAfter, and its other fields are ignored.
+// The certificate is an end-entity certificate whose key usage is limited
+// to keyEncipherment. priv must be an ECDSA, Ed25519 or RSA key matching
+// parent.
+func Issue(req *Request, template, parent *x509.Certificate, priv crypto.Signer) ([]byte, error) {
+	if err := req.Verify(); err != nil {
+		return nil, err
+	}
+	if template.SerialNumber == nil || template.SerialNumber.Sign() <= 0 {
+		return nil, errors.New("certificate serial number must be positive")
+	}
+	if !template.NotAfter.After(template.NotBefore) {
+		return nil, errors.New("certificate NotAfter must be after NotBefore")
+	}
+	sigAlg, hash, err := signatureAlgorithm(priv.Public())
+	if err != nil {
+		return nil, err
+	}
+	subject, err := asn1.Marshal(req.Subject.ToRDNSequence())
+	if err != nil {
+		return nil, err
+	}
+	spki, err := zkpop.MarshalPKIXPublicKey(req.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	exts, err := extensions(req, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs, err := asn1.Marshal(tbsCertificate{
+		Version:            2,
+		SerialNumber:       template.SerialNumber,
+		SignatureAlgorithm: sigAlg,
+		Issuer:             asn1.RawValue{FullBytes: parent.RawSubject},
+		Validity: validity{
+			template.NotBefore.UTC().Truncate(time.Second),
+			template.NotAfter.UTC().Truncate(time.Second),
+		},
+		Subject:    asn1.RawValue{FullBytes: subject},
+		PublicKey:  asn1.RawValue{FullBytes: spki},
+		Extensions: exts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	digest := tbs
+	if hash != 0 {
+		h := hash.New()
+		h.Write(tbs)
+		digest = h.Sum(nil)
+	}
+	sig, err := priv.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	der, err := asn1.Marshal(certificate{
+		TBS:                asn1.RawValue{FullBytes: tbs},
+		SignatureAlgorithm: sigAlg,
+		Signature:          asn1.BitString{Bytes: sig, BitLength: 8 * len(sig)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the result with crypto/x509, which also catches a priv that
+	// does not belong to parent.
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	if err := cert.CheckSignatureFrom(parent); err != nil {
+		return nil, fmt.Errorf("issued certificate does not verify: %w", err)
+	}
+	return der, nil
+}
+
+func extensions(req *Request, parent *x509.Certificate) ([]pkix.Extension, error) {
+	keyUsage, err := asn1.Marshal(asn1.BitString{Bytes: []byte{0x20}, BitLength: 3})
+	if err != nil {
+		return nil, err
+	}
+	basicConstraints, err := asn1.Marshal(struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	// Key identifier method 1 of RFC 7093: the leftmost 160 bits of the
+	// SHA-256 hash of the public key.
+	sum := sha256.Sum256(req.PublicKey.Bytes())
+	skid, err := asn1.Marshal(sum[:20])
+	if err != nil {
+		return nil, err
+	}
+	exts := []pkix.Extension{
+		{Id: oidExtensionKeyUsage, Critical: true, Value: keyUsage},
+		{Id: oidExtensionBasicConstraint, Critical: true, Value: basicConstraints},
+		{Id: oidExtensionSubjectKeyID, Value: skid},
+	}
+	if len(parent.SubjectKeyId) > 0 {
+		akid, err := asn1.Marshal(authorityKeyID{parent.SubjectKeyId})
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, pkix.Extension{Id: oidExtensionAuthorityKeyID, Value: akid})
+	}
+	if len(req.DNSNames) > 0 {
+		san, err := marshalSAN(req.DNSNames)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, pkix.Extension{Id: oidExtensionSAN, Value: san})
+	}
+	return exts, nil
+}
+
+// signatureAlgorithm picks the signature algorithm for a CA key, the same
+// defaults crypto/x509 uses.
+func signatureAlgorithm(pub crypto.PublicKey) (pkix.AlgorithmIdentifier, crypto.Hash, error) {
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSAWithSHA256}, crypto.SHA256, nil
+		case elliptic.P384():
+			return pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSAWithSHA384}, crypto.SHA384, nil
+		case elliptic.P521():
+			return pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSAWithSHA512}, crypto.SHA512, nil
+		}
+	case ed25519.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureEd25519}, 0, nil
+	case *rsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureSHA256WithRSA, Parameters: asn1.NullRawValue}, crypto.SHA256, nil
+	}
+	return pkix.AlgorithmIdentifier{}, 0, fmt.Errorf("unsupported CA key type %T", pub)
+}
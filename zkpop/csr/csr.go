@@ -0,0 +1,189 @@
+// Package csr creates and parses certification requests for KEM keys and
+// issues X.509 certificates for them.
+//
+// A KEM key cannot sign its own PKCS#10 request, which is how a CA normally
+// checks that the requester holds the private key. Requests made by this
+// package are instead unsigned (signature algorithm id-alg-unsigned, empty
+// signature) and carry a NIZKPoP for the key in an attribute:
+//
+//	id-aa-nizkpop ATTRIBUTE ::= { WITH SYNTAX NIZKPoP ID 1.3.9999.77.3.1 }
+//
+// where NIZKPoP is the DER structure from package zkpop. The CA verifies
+// the proof before issuing a certificate, see Issue.
+package csr
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"slices"
+
+	"zkpop-go/zkpop"
+)
+
+var (
+	// oidAttributeNIZKPoP lives under the experimental arc of package zkpop.
+	oidAttributeNIZKPoP      = asn1.ObjectIdentifier{1, 3, 9999, 77, 3, 1}
+	oidAttributeExtensionReq = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}
+	oidExtensionSAN          = asn1.ObjectIdentifier{2, 5, 29, 17}
+	// oidUnsigned is id-alg-unsigned from the LAMPS unsigned certificates
+	// work, for structures that have a signature field but no signature.
+	oidUnsigned = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 6, 36}
+)
+
+// ErrMissingProof is returned by ParseRequest for a request without a
+// NIZKPoP attribute.
+var ErrMissingProof = errors.New("certification request has no NIZKPoP")
+
+// Request is a certification request for a KEM public key.
+type Request struct {
+	// Raw is the complete DER request, as produced by CreateRequest.
+	Raw []byte
+
+	Subject   pkix.Name
+	DNSNames  []string
+	PublicKey *zkpop.PublicKey
+	Proof     *zkpop.Envelope
+}
+
+type certificationRequest struct {
+	Info               asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+}
+
+type certificationRequestInfo struct {
+	Raw        asn1.RawContent
+	Version    int
+	Subject    asn1.RawValue
+	PublicKey  asn1.RawValue
+	Attributes []asn1.RawValue `asn1:"tag:0"`
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// CreateRequest returns a DER certification request for template.PublicKey
+// carrying template.Proof. Subject and DNSNames are copied from template;
+// Raw is ignored. The proof is not verified here; the CA does that.
+func CreateRequest(template *Request) ([]byte, error) {
+	if template.PublicKey == nil || template.Proof == nil {
+		return nil, errors.New("request needs a public key and a proof")
+	}
+	subject, err := asn1.Marshal(template.Subject.ToRDNSequence())
+	if err != nil {
+		return nil, err
+	}
+	spki, err := zkpop.MarshalPKIXPublicKey(template.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	pop, err := zkpop.MarshalNIZKPoP(template.Proof)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute{{oidAttributeNIZKPoP, []asn1.RawValue{{FullBytes: pop}}}}
+	if len(template.DNSNames) > 0 {
+		san, err := marshalSAN(template.DNSNames)
+		if err != nil {
+			return nil, err
+		}
+		exts, err := asn1.Marshal([]pkix.Extension{{Id: oidExtensionSAN, Value: san}})
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attribute{oidAttributeExtensionReq, []asn1.RawValue{{FullBytes: exts}}})
+	}
+	// DER sorts the elements of a SET OF by their encoding.
+	rawAttrs := make([]asn1.RawValue, len(attrs))
+	for i, a := range attrs {
+		b, err := asn1.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		rawAttrs[i] = asn1.RawValue{FullBytes: b}
+	}
+	slices.SortFunc(rawAttrs, func(a, b asn1.RawValue) int { return bytes.Compare(a.FullBytes, b.FullBytes) })
+
+	info, err := asn1.Marshal(certificationRequestInfo{
+		Subject:    asn1.RawValue{FullBytes: subject},
+		PublicKey:  asn1.RawValue{FullBytes: spki},
+		Attributes: rawAttrs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(certificationRequest{
+		Info:               asn1.RawValue{FullBytes: info},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidUnsigned},
+	})
+}
+
+// ParseRequest parses a DER certification request made by CreateRequest.
+// It does not verify the proof; see Request.Verify.
+func ParseRequest(der []byte) (*Request, error) {
+	// crypto/x509 handles the subject and the extension request. It leaves
+	// the public key and the signature alone, as it knows neither
+	// algorithm.
+	xr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	var outer certificationRequest
+	if _, err := asn1.Unmarshal(der, &outer); err != nil {
+		return nil, err
+	}
+	if !outer.SignatureAlgorithm.Algorithm.Equal(oidUnsigned) || len(outer.Signature.Bytes) != 0 {
+		return nil, fmt.Errorf("unsupported request signature algorithm %s", outer.SignatureAlgorithm.Algorithm)
+	}
+	var info certificationRequestInfo
+	if _, err := asn1.Unmarshal(xr.RawTBSCertificateRequest, &info); err != nil {
+		return nil, err
+	}
+	pk, err := zkpop.ParsePKIXPublicKey(xr.RawSubjectPublicKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	r := &Request{Raw: xr.Raw, Subject: xr.Subject, DNSNames: xr.DNSNames, PublicKey: pk}
+	for _, raw := range info.Attributes {
+		var a attribute
+		if _, err := asn1.Unmarshal(raw.FullBytes, &a); err != nil {
+			return nil, err
+		}
+		if !a.Type.Equal(oidAttributeNIZKPoP) {
+			continue
+		}
+		if r.Proof != nil || len(a.Values) != 1 {
+			return nil, errors.New("certification request must carry exactly one NIZKPoP")
+		}
+		if r.Proof, err = zkpop.ParseNIZKPoP(a.Values[0].FullBytes); err != nil {
+			return nil, err
+		}
+	}
+	if r.Proof == nil {
+		return nil, ErrMissingProof
+	}
+	return r, nil
+}
+
+// Verify checks the request's NIZKPoP against its public key.
+func (r *Request) Verify() error {
+	if r.Proof == nil {
+		return ErrMissingProof
+	}
+	return r.Proof.Verify(r.PublicKey)
+}
+
+func marshalSAN(dnsNames []string) ([]byte, error) {
+	names := make([]asn1.RawValue, len(dnsNames))
+	for i, n := range dnsNames {
+		names[i] = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, Bytes: []byte(n)}
+	}
+	return asn1.Marshal(names)
+}
@@ -0,0 +1,188 @@
+package zkpop
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// DER encodings for keys and proofs, for use in X.509 and PKCS structures.
+//
+// Public keys are SubjectPublicKeyInfo (RFC 5280) and private keys are
+// OneAsymmetricKey (RFC 5958), both identified by the OID of the plain KEM
+// (see OID) with absent parameters. The key material is the raw encoding of
+// the C implementation; for ML-KEM the private key is the 64-byte seed.
+//
+// A proof of possession is encoded as
+//
+//	NIZKPoP ::= SEQUENCE {
+//	    algorithm      AlgorithmIdentifier, -- NIZKPoP OID, ZKPoPParameters
+//	    publicKeyHash  OCTET STRING,        -- SHA-256 of the public key
+//	    proof          OCTET STRING }
+//
+//	ZKPoPParameters ::= SEQUENCE {
+//	    profile  INTEGER (0..255),
+//	    n        INTEGER,                   -- ZKPOP_N
+//	    tau      INTEGER }                  -- ZKPOP_TAU
+//
+// which carries the same information as the binary Envelope format.
+
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type oneAsymmetricKey struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+	Attributes asn1.RawValue  `asn1:"optional,tag:0"`
+	PublicKey  asn1.BitString `asn1:"optional,tag:1"`
+}
+
+type nizkpopInfo struct {
+	Algorithm     nizkpopAlgorithm
+	PublicKeyHash []byte
+	Proof         []byte
+}
+
+type nizkpopAlgorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters nizkpopParameters
+}
+
+type nizkpopParameters struct {
+	Profile int
+	N       int64
+	Tau     int64
+}
+
+// MarshalPKIXPublicKey encodes pk as a DER SubjectPublicKeyInfo.
+func MarshalPKIXPublicKey(pk *PublicKey) ([]byte, error) {
+	oid, err := OID(keyScheme(pk.scheme))
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oid},
+		PublicKey: asn1.BitString{Bytes: pk.b, BitLength: 8 * len(pk.b)},
+	})
+}
+
+// ParsePKIXPublicKey decodes a DER SubjectPublicKeyInfo holding a key of
+// one of the KEMs in this build.
+func ParsePKIXPublicKey(der []byte) (*PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, fmt.Errorf("failed to parse SubjectPublicKeyInfo: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data after SubjectPublicKeyInfo")
+	}
+	scheme, err := keyAlgorithm(spki.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if spki.PublicKey.BitLength != 8*len(spki.PublicKey.Bytes) {
+		return nil, errors.New("public key is not a whole number of bytes")
+	}
+	return NewPublicKey(scheme, spki.PublicKey.Bytes)
+}
+
+// MarshalPKCS8PrivateKey encodes sk as a DER OneAsymmetricKey (version 1,
+// without the optional public key).
+func MarshalPKCS8PrivateKey(sk *PrivateKey) ([]byte, error) {
+	oid, err := OID(keyScheme(sk.scheme))
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(oneAsymmetricKey{
+		Algorithm:  pkix.AlgorithmIdentifier{Algorithm: oid},
+		PrivateKey: sk.b,
+	})
+}
+
+// ParsePKCS8PrivateKey decodes a DER OneAsymmetricKey of version 1 or 2. If
+// a version 2 key includes its public key, the size of that key is checked.
+func ParsePKCS8PrivateKey(der []byte) (*PrivateKey, error) {
+	var k oneAsymmetricKey
+	if rest, err := asn1.Unmarshal(der, &k); err != nil {
+		return nil, fmt.Errorf("failed to parse OneAsymmetricKey: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data after OneAsymmetricKey")
+	}
+	if k.Version != 0 && k.Version != 1 {
+		return nil, fmt.Errorf("unsupported OneAsymmetricKey version %d", k.Version)
+	}
+	scheme, err := keyAlgorithm(k.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if len(k.PublicKey.Bytes) != 0 {
+		if k.Version == 0 {
+			return nil, errors.New("public key in a version 1 OneAsymmetricKey")
+		}
+		if err := checkSize(ErrInvalidPublicKeySize, scheme.Name(), k.PublicKey.Bytes, scheme.PublicKeySize()); err != nil {
+			return nil, err
+		}
+	}
+	return NewPrivateKey(scheme, k.PrivateKey)
+}
+
+// MarshalNIZKPoP encodes e as a DER NIZKPoP structure.
+func MarshalNIZKPoP(e *Envelope) ([]byte, error) {
+	oid, err := OID(e.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(nizkpopInfo{
+		Algorithm: nizkpopAlgorithm{oid, nizkpopParameters{
+			Profile: int(e.profile.ID),
+			N:       int64(e.profile.N),
+			Tau:     int64(e.profile.Tau),
+		}},
+		PublicKeyHash: e.pkHash[:],
+		Proof:         e.body,
+	})
+}
+
+// ParseNIZKPoP decodes a DER NIZKPoP structure. As with
+// Envelope.UnmarshalBinary, the scheme and profile must be part of this
+// build.
+func ParseNIZKPoP(der []byte) (*Envelope, error) {
+	var info nizkpopInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse NIZKPoP: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data after NIZKPoP")
+	}
+	k, err := LookupOID(info.Algorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	scheme, ok := k.(ProvingKEM)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a NIZKPoP scheme", k.Name())
+	}
+	p := info.Algorithm.Parameters
+	if p.Profile < 0 || p.Profile > 255 || p.N < 0 || p.Tau < 0 {
+		return nil, fmt.Errorf("%w: profile parameters out of range", ErrMalformedEnvelope)
+	}
+	return decodedEnvelope(scheme, uint8(p.Profile), uint64(p.N), uint64(p.Tau), info.PublicKeyHash, info.Proof)
+}
+
+// keyAlgorithm returns the KEM identified by a key's AlgorithmIdentifier.
+// Keys are always identified by the plain KEM, never its NIZKPoP variant.
+func keyAlgorithm(a pkix.AlgorithmIdentifier) (KEM, error) {
+	if len(a.Parameters.FullBytes) != 0 {
+		return nil, errors.New("unexpected parameters in key AlgorithmIdentifier")
+	}
+	k, err := LookupOID(a.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := k.(ProvingKEM); ok {
+		return nil, fmt.Errorf("%s identifies a proof, not a key", k.Name())
+	}
+	return k, nil
+}
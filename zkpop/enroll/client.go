@@ -0,0 +1,73 @@
+package enroll
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseBytes bounds the responses the client reads.
+const maxResponseBytes = 1 << 20
+
+// Client talks to an enrollment server.
+type Client struct {
+	// URL is the base URL of the server, such as "http://localhost:8443".
+	URL string
+	// HTTPClient is used for requests. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Enroll sends req and returns the issued certificate. If the server
+// rejects the request the error is a *Rejection.
+func (c *Client) Enroll(ctx context.Context, req *Request) (*x509.Certificate, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.post(ctx, "application/json", body)
+}
+
+// EnrollCSR sends a DER certification request made by csr.CreateRequest
+// and returns the issued certificate.
+func (c *Client) EnrollCSR(ctx context.Context, der []byte) (*x509.Certificate, error) {
+	return c.post(ctx, "application/pkcs10", der)
+}
+
+func (c *Client) post(ctx context.Context, contentType string, body []byte) (*x509.Certificate, error) {
+	hr, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.URL, "/")+Path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hr.Header.Set("Content-Type", contentType)
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(hr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		rej := &Rejection{}
+		if err := json.Unmarshal(b, rej); err != nil || rej.Code == "" {
+			return nil, fmt.Errorf("enrollment failed: %s", resp.Status)
+		}
+		rej.Status = resp.StatusCode
+		return nil, rej
+	}
+	var r Response
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("invalid enrollment response: %w", err)
+	}
+	return x509.ParseCertificate(r.Certificate)
+}
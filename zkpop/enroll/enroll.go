@@ -0,0 +1,85 @@
+// Package enroll implements a small HTTP enrollment protocol for KEM keys,
+// in the spirit of EST simpleenroll: the client posts a public key with its
+// NIZKPoP, and the server verifies the proof and answers with a certificate
+// issued by package csr, or with a machine-readable rejection.
+//
+// Requests are POSTed to /enroll either as JSON (Request) or, as in EST, as
+// a certification request from package csr with Content-Type
+// application/pkcs10 (DER, or base64 DER). Successful responses are JSON
+// (Response); rejections carry a JSON Rejection and an HTTP error status.
+package enroll
+
+import (
+	"fmt"
+
+	"zkpop-go/zkpop"
+)
+
+// Path is the URL path of the enrollment endpoint.
+const Path = "/enroll"
+
+// Request is the JSON body of an enrollment request.
+type Request struct {
+	// Algorithm is the NIZKPoP scheme that produced Proof, such as
+	// "Kyber768-NIZKPoP".
+	Algorithm string `json:"alg"`
+	// Profile is the proof profile, see zkpop.Profile. Zero is the
+	// default profile of the scheme.
+	Profile uint8 `json:"profile,omitempty"`
+
+	CommonName string   `json:"common_name,omitempty"`
+	DNSNames   []string `json:"dns_names,omitempty"`
+
+	PublicKey []byte `json:"public_key"`
+	Proof     []byte `json:"proof"`
+}
+
+// NewRequest returns a request for pk with its proof, using the default
+// profile of the proof's scheme.
+func NewRequest(pk *zkpop.PublicKey, proof *zkpop.Proof, commonName string, dnsNames ...string) *Request {
+	return &Request{
+		Algorithm:  proof.Scheme().Name(),
+		CommonName: commonName,
+		DNSNames:   dnsNames,
+		PublicKey:  pk.Bytes(),
+		Proof:      proof.Bytes(),
+	}
+}
+
+// Response is the JSON body of a successful enrollment.
+type Response struct {
+	// Certificate is the issued certificate in DER.
+	Certificate []byte `json:"certificate"`
+}
+
+// Code classifies a rejection.
+type Code string
+
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeUnsupportedAlgorithm Code = "unsupported_algorithm"
+	CodeTooLarge             Code = "too_large"
+	CodeRateLimited          Code = "rate_limited"
+	CodeProofRejected        Code = "proof_rejected"
+	CodeInternal             Code = "internal_error"
+)
+
+// Rejection is the JSON body of a failed enrollment. The client returns it
+// as an error.
+type Rejection struct {
+	// Status is the HTTP status code. It is not part of the JSON body.
+	Status int `json:"-"`
+
+	Code Code `json:"error"`
+	// Reason is the zkpop.VerifyReason, as a string, when Code is
+	// CodeProofRejected.
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+func (r *Rejection) Error() string {
+	if r.Reason != "" {
+		return fmt.Sprintf("enrollment rejected (%s, %s): %s", r.Code, r.Reason, r.Message)
+	}
+	return fmt.Sprintf("enrollment rejected (%s): %s", r.Code, r.Message)
+}
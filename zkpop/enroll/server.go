@@ -0,0 +1,304 @@
+package enroll
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"zkpop-go/zkpop"
+	"zkpop-go/zkpop/csr"
+)
+
+// Defaults for the zero values of the Server limits.
+const (
+	DefaultMaxBodyBytes  = 8 << 20
+	DefaultRate          = 1.0
+	DefaultBurst         = 5
+	DefaultMaxConcurrent = 4
+	DefaultValidity      = 90 * 24 * time.Hour
+)
+
+// Server is an http.Handler serving the enrollment endpoint. Set the fields
+// before the first request and do not change them afterwards.
+type Server struct {
+	// CACert and CAKey issue the certificates. CAKey must be an ECDSA,
+	// Ed25519 or RSA key; see csr.Issue.
+	CACert *x509.Certificate
+	CAKey  crypto.Signer
+
+	// Algorithms restricts enrollment to the named NIZKPoP schemes. If
+	// empty, every scheme in zkpop.All that implements zkpop.ProvingKEM is
+	// accepted.
+	Algorithms []string
+
+	// Validity is the lifetime of issued certificates.
+	Validity time.Duration
+
+	// MaxBodyBytes bounds the request body. Requests are further checked
+	// against the key and proof sizes of the declared scheme.
+	MaxBodyBytes int64
+
+	// Rate and Burst limit requests per client IP address, in requests per
+	// second with a bucket of Burst requests.
+	Rate  float64
+	Burst int
+
+	// MaxConcurrent bounds how many proofs are verified at once. Requests
+	// beyond it are rejected as rate limited rather than queued.
+	MaxConcurrent int
+
+	// ErrorLog receives internal errors. If nil, the log package is used.
+	ErrorLog *log.Logger
+
+	once    sync.Once
+	initErr error
+	limiter *limiter
+	slots   chan struct{}
+}
+
+// Init applies the defaults and validates the configuration. ServeHTTP
+// calls it on the first request, but calling it first reports errors early.
+func (s *Server) Init() error {
+	s.once.Do(func() { s.initErr = s.init() })
+	return s.initErr
+}
+
+func (s *Server) init() error {
+	if s.CACert == nil || s.CAKey == nil {
+		return errors.New("enroll: CA certificate and key are required")
+	}
+	for _, name := range s.Algorithms {
+		if _, err := provingScheme(name); err != nil {
+			return fmt.Errorf("enroll: %w", err)
+		}
+	}
+	if s.Validity <= 0 {
+		s.Validity = DefaultValidity
+	}
+	if s.MaxBodyBytes <= 0 {
+		s.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if s.Rate <= 0 {
+		s.Rate = DefaultRate
+	}
+	if s.Burst <= 0 {
+		s.Burst = DefaultBurst
+	}
+	if s.MaxConcurrent <= 0 {
+		s.MaxConcurrent = DefaultMaxConcurrent
+	}
+	s.limiter = newLimiter(s.Rate, s.Burst)
+	s.slots = make(chan struct{}, s.MaxConcurrent)
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.Init(); err != nil {
+		s.logf("%v", err)
+		s.reject(w, &Rejection{Status: http.StatusInternalServerError, Code: CodeInternal, Message: "server misconfigured"})
+		return
+	}
+	if r.URL.Path != Path {
+		s.reject(w, &Rejection{Status: http.StatusNotFound, Code: CodeBadRequest, Message: "unknown endpoint"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		s.reject(w, &Rejection{Status: http.StatusMethodNotAllowed, Code: CodeBadRequest, Message: "use POST"})
+		return
+	}
+	if ok, wait := s.limiter.allow(clientIP(r), time.Now()); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait/time.Second)+1))
+		s.reject(w, &Rejection{Status: http.StatusTooManyRequests, Code: CodeRateLimited, Message: "too many requests"})
+		return
+	}
+	if r.ContentLength > s.MaxBodyBytes {
+		s.reject(w, tooLarge(s.MaxBodyBytes))
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.MaxBodyBytes))
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			s.reject(w, tooLarge(s.MaxBodyBytes))
+		} else {
+			s.reject(w, &Rejection{Status: http.StatusBadRequest, Code: CodeBadRequest, Message: "failed to read request"})
+		}
+		return
+	}
+
+	var req *csr.Request
+	var rej *Rejection
+	switch ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct {
+	case "application/json":
+		req, rej = s.parseJSON(body)
+	case "application/pkcs10":
+		req, rej = s.parsePKCS10(body)
+	default:
+		rej = &Rejection{Status: http.StatusUnsupportedMediaType, Code: CodeBadRequest,
+			Message: "Content-Type must be application/json or application/pkcs10"}
+	}
+	if rej != nil {
+		s.reject(w, rej)
+		return
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		w.Header().Set("Retry-After", "1")
+		s.reject(w, &Rejection{Status: http.StatusServiceUnavailable, Code: CodeRateLimited, Message: "server busy"})
+		return
+	}
+	der, err := s.issue(req)
+	if err != nil {
+		var ve *zkpop.VerifyError
+		if errors.As(err, &ve) {
+			rej := &Rejection{Status: http.StatusForbidden, Code: CodeProofRejected,
+				Reason: ve.Reason.String(), Message: ve.Error()}
+			if ve.Reason == zkpop.ReasonTooLarge {
+				rej.Status, rej.Code = http.StatusRequestEntityTooLarge, CodeTooLarge
+			}
+			s.reject(w, rej)
+			return
+		}
+		s.logf("enroll: issuing certificate: %v", err)
+		s.reject(w, &Rejection{Status: http.StatusInternalServerError, Code: CodeInternal, Message: "failed to issue certificate"})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Response{Certificate: der})
+}
+
+func (s *Server) parseJSON(body []byte) (*csr.Request, *Rejection) {
+	var jr Request
+	if err := json.Unmarshal(body, &jr); err != nil {
+		return nil, badRequest("invalid JSON: %v", err)
+	}
+	scheme, rej := s.scheme(jr.Algorithm)
+	if rej != nil {
+		return nil, rej
+	}
+	pk, err := zkpop.NewPublicKey(scheme, jr.PublicKey)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	env, err := zkpop.NewEnvelope(scheme, jr.Profile, jr.PublicKey, jr.Proof)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return &csr.Request{
+		Subject:   pkix.Name{CommonName: jr.CommonName},
+		DNSNames:  jr.DNSNames,
+		PublicKey: pk,
+		Proof:     env,
+	}, nil
+}
+
+func (s *Server) parsePKCS10(body []byte) (*csr.Request, *Rejection) {
+	der := body
+	// EST sends base64; accept raw DER as well.
+	if b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), "")); err == nil {
+		der = b
+	}
+	req, err := csr.ParseRequest(der)
+	if err != nil {
+		if errors.Is(err, zkpop.ErrUnknownAlgorithm) {
+			return nil, &Rejection{Status: http.StatusBadRequest, Code: CodeUnsupportedAlgorithm, Message: err.Error()}
+		}
+		return nil, badRequest("invalid certification request: %v", err)
+	}
+	if _, rej := s.scheme(req.Proof.Scheme().Name()); rej != nil {
+		return nil, rej
+	}
+	return req, nil
+}
+
+// scheme returns the allowed NIZKPoP scheme with the given name.
+func (s *Server) scheme(name string) (zkpop.ProvingKEM, *Rejection) {
+	k, err := provingScheme(name)
+	if err == nil && len(s.Algorithms) > 0 {
+		err = fmt.Errorf("%w %q", zkpop.ErrUnknownAlgorithm, name)
+		for _, a := range s.Algorithms {
+			if strings.EqualFold(a, k.Name()) {
+				err = nil
+			}
+		}
+	}
+	if err != nil {
+		return nil, &Rejection{Status: http.StatusBadRequest, Code: CodeUnsupportedAlgorithm, Message: err.Error()}
+	}
+	return k, nil
+}
+
+func (s *Server) issue(req *csr.Request) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return csr.Issue(req, &x509.Certificate{
+		SerialNumber: serial.Add(serial, big.NewInt(1)),
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(s.Validity),
+	}, s.CACert, s.CAKey)
+}
+
+func (s *Server) reject(w http.ResponseWriter, rej *Rejection) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rej.Status)
+	json.NewEncoder(w).Encode(rej)
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+func provingScheme(name string) (zkpop.ProvingKEM, error) {
+	k, err := zkpop.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := k.(zkpop.ProvingKEM)
+	if !ok {
+		return nil, fmt.Errorf("%w %q: no NIZKPoP", zkpop.ErrUnknownAlgorithm, name)
+	}
+	return p, nil
+}
+
+func badRequest(format string, args ...any) *Rejection {
+	return &Rejection{Status: http.StatusBadRequest, Code: CodeBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+func tooLarge(limit int64) *Rejection {
+	return &Rejection{Status: http.StatusRequestEntityTooLarge, Code: CodeTooLarge,
+		Message: fmt.Sprintf("request body exceeds %d bytes", limit)}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
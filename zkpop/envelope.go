@@ -0,0 +1,188 @@
+package zkpop
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// An Envelope is a self-describing NIZKPoP: besides the proof it records
+// the scheme and profile that produced it and a hash of the public key it
+// was made for, so a verifier needs no out-of-band parameters.
+//
+// The binary encoding (version 1) is, with integers in big-endian order:
+//
+//	magic    [4]byte  "ZKPP"
+//	version  uint8    1
+//	scheme   uint16   last arc of the scheme's NIZKPoP OID
+//	profile  uint8    profile ID
+//	N        uint32   ZKPOP_N of the profile
+//	TAU      uint16   ZKPOP_TAU of the profile
+//	pkHash   [32]byte SHA-256 of the public key
+//	bodyLen  uint32
+//	body     [bodyLen]byte
+type Envelope struct {
+	scheme  ProvingKEM
+	profile Profile
+	pkHash  [sha256.Size]byte
+	body    []byte
+}
+
+const (
+	envelopeMagic      = "ZKPP"
+	envelopeVersion    = 1
+	envelopeHeaderSize = 4 + 1 + 2 + 1 + 4 + 2 + sha256.Size + 4
+)
+
+var (
+	// ErrMalformedEnvelope is returned by UnmarshalBinary for input that is
+	// not a well-formed envelope.
+	ErrMalformedEnvelope = errors.New("malformed proof envelope")
+	// ErrKeyMismatch is reported when an envelope is checked against a
+	// public key other than the one it was made for.
+	ErrKeyMismatch = errors.New("proof was made for a different public key")
+)
+
+// NewEnvelope wraps proof, made by scheme with the given profile, for pk.
+func NewEnvelope(scheme ProvingKEM, profile uint8, pk, proof []byte) (*Envelope, error) {
+	p, err := findProfile(scheme, profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, scheme.Name(), pk, scheme.PublicKeySize()); err != nil {
+		return nil, err
+	}
+	if len(proof) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyProof, scheme.Name())
+	}
+	return &Envelope{scheme, p, sha256.Sum256(pk), bytes.Clone(proof)}, nil
+}
+
+// Envelope wraps p, which uses the default profile of its scheme, for pk.
+func (p *Proof) Envelope(pk *PublicKey) (*Envelope, error) {
+	return NewEnvelope(p.scheme, p.scheme.Profiles()[0].ID, pk.b, p.b)
+}
+
+// Scheme returns the scheme that produced the proof.
+func (e *Envelope) Scheme() ProvingKEM { return e.scheme }
+
+// Profile returns the profile the proof was made with.
+func (e *Envelope) Profile() Profile { return e.profile }
+
+// Body returns a copy of the proof itself.
+func (e *Envelope) Body() []byte { return bytes.Clone(e.body) }
+
+// MarshalBinary encodes e in the version 1 envelope format.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	id, err := proofSchemeID(e.scheme)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, envelopeHeaderSize+len(e.body))
+	b = append(b, envelopeMagic...)
+	b = append(b, envelopeVersion)
+	b = binary.BigEndian.AppendUint16(b, id)
+	b = append(b, e.profile.ID)
+	b = binary.BigEndian.AppendUint32(b, uint32(e.profile.N))
+	b = binary.BigEndian.AppendUint16(b, uint16(e.profile.Tau))
+	b = append(b, e.pkHash[:]...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(e.body)))
+	return append(b, e.body...), nil
+}
+
+// UnmarshalBinary decodes an envelope produced by MarshalBinary. The scheme
+// and profile must be part of this build, and the declared N and TAU must
+// match the profile with that ID.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if len(data) < envelopeHeaderSize {
+		return fmt.Errorf("%w: %d bytes, want at least %d", ErrMalformedEnvelope, len(data), envelopeHeaderSize)
+	}
+	if string(data[:4]) != envelopeMagic {
+		return fmt.Errorf("%w: bad magic", ErrMalformedEnvelope)
+	}
+	if data[4] != envelopeVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrMalformedEnvelope, data[4])
+	}
+	scheme, err := proofSchemeByID(binary.BigEndian.Uint16(data[5:]))
+	if err != nil {
+		return err
+	}
+	body := data[envelopeHeaderSize:]
+	if size := binary.BigEndian.Uint32(data[envelopeHeaderSize-4:]); uint64(size) != uint64(len(body)) {
+		return fmt.Errorf("%w: body is %d bytes, header says %d", ErrMalformedEnvelope, len(body), size)
+	}
+	d, err := decodedEnvelope(scheme, data[7], uint64(binary.BigEndian.Uint32(data[8:])),
+		uint64(binary.BigEndian.Uint16(data[12:])), data[14:14+sha256.Size], body)
+	if err != nil {
+		return err
+	}
+	*e = *d
+	return nil
+}
+
+// decodedEnvelope checks the fields of an encoded envelope against this
+// build and assembles them.
+func decodedEnvelope(scheme ProvingKEM, id uint8, n, tau uint64, pkHash, body []byte) (*Envelope, error) {
+	p, err := findProfile(scheme, id)
+	if err != nil {
+		return nil, err
+	}
+	if n != uint64(p.N) || tau != uint64(p.Tau) {
+		return nil, fmt.Errorf("%w: %s %s declared as N=%d, TAU=%d", ErrMalformedEnvelope, scheme.Name(), p, n, tau)
+	}
+	if len(pkHash) != sha256.Size {
+		return nil, fmt.Errorf("%w: public key hash is %d bytes, want %d", ErrMalformedEnvelope, len(pkHash), sha256.Size)
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyProof, scheme.Name())
+	}
+	e := &Envelope{scheme: scheme, profile: p, body: bytes.Clone(body)}
+	copy(e.pkHash[:], pkHash)
+	return e, nil
+}
+
+// Verify checks the proof against pk. Keys of a scheme other than the one
+// the envelope declares, and keys other than the one the proof was made
+// for, are rejected without calling into the C verifier.
+func (e *Envelope) Verify(pk *PublicKey) error {
+	if keyScheme(pk.scheme) != keyScheme(e.scheme) {
+		return &VerifyError{Scheme: e.scheme.Name(), Reason: ReasonWrongParameterSet,
+			Err: errors.New("public key belongs to " + pk.scheme.Name())}
+	}
+	if sha256.Sum256(pk.b) != e.pkHash {
+		return &VerifyError{Scheme: e.scheme.Name(), Reason: ReasonKeyMismatch, Err: ErrKeyMismatch}
+	}
+	return e.scheme.VerifyProfiledProof(pk.b, append([]byte{e.profile.ID}, e.body...))
+}
+
+func findProfile(scheme ProvingKEM, id uint8) (Profile, error) {
+	for _, p := range scheme.Profiles() {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("%w: %s has no profile %d", ErrUnknownProfile, scheme.Name(), id)
+}
+
+// proofSchemeID returns the envelope scheme ID of k, the last arc of its
+// NIZKPoP OID.
+func proofSchemeID(k ProvingKEM) (uint16, error) {
+	oid, err := OID(k)
+	if err != nil {
+		return 0, err
+	}
+	if len(oid) != len(oidNIZKPoP)+1 || !oid[:len(oidNIZKPoP)].Equal(oidNIZKPoP) {
+		return 0, fmt.Errorf("%s has no envelope scheme ID", k.Name())
+	}
+	return uint16(oid[len(oidNIZKPoP)]), nil
+}
+
+func proofSchemeByID(id uint16) (ProvingKEM, error) {
+	k, err := LookupOID(nizkpopOID(int(id)))
+	if err != nil {
+		return nil, err
+	}
+	return k.(ProvingKEM), nil
+}
@@ -0,0 +1,28 @@
+package zkpop
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned when an input does not have the size the C code expects.
+// The bindings check sizes before taking the address of the first element,
+// so the C side never reads past the end of a Go slice.
+var (
+	ErrInvalidPublicKeySize  = errors.New("invalid public key size")
+	ErrInvalidPrivateKeySize = errors.New("invalid private key size")
+	ErrInvalidCiphertextSize = errors.New("invalid ciphertext size")
+	ErrInvalidSeedSize       = errors.New("invalid seed size")
+	ErrEmptyProof            = errors.New("empty proof")
+	ErrProofTruncated        = errors.New("proof truncated")
+	ErrProofTooLarge         = errors.New("proof too large")
+)
+
+// checkSize returns err annotated with the scheme and sizes if b is not
+// exactly want bytes long.
+func checkSize(err error, scheme string, b []byte, want int) error {
+	if len(b) != want {
+		return fmt.Errorf("%w: %s expects %d bytes, got %d", err, scheme, want, len(b))
+	}
+	return nil
+}
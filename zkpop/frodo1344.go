@@ -0,0 +1,151 @@
+package zkpop
+
+/*
+#cgo LDFLAGS: ${SRCDIR}/../external/KEM-NIZKPoP/frodo-zkpop/frodo1344/libfrodo.a
+#include "api_frodo1344.h"
+#include <stdint.h>
+#include <stdlib.h>
+enum {
+	frodo1344_publickeybytes = CRYPTO_PUBLICKEYBYTES,
+	frodo1344_secretkeybytes = CRYPTO_SECRETKEYBYTES,
+	frodo1344_ciphertextbytes = CRYPTO_CIPHERTEXTBYTES,
+	frodo1344_bytes = CRYPTO_BYTES,
+};
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// KeyPairFrodo1344 generates a FrodoKEM-1344 key pair.
+func KeyPairFrodo1344() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	pk := make([]byte, C.frodo1344_publickeybytes)
+	sk := make([]byte, C.frodo1344_secretkeybytes)
+
+	ret := C.crypto_kem_keypair_Frodo1344(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to generate Frodo1344 keypair: %d", ret)
+	}
+	return pk, sk, nil
+}
+
+// EncapsFrodo1344 returns a ciphertext and a 32-byte shared secret for pk.
+func EncapsFrodo1344(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "FrodoKEM-1344", pk, C.frodo1344_publickeybytes); err != nil {
+		return nil, nil, err
+	}
+	ss = make([]byte, C.frodo1344_bytes)
+	ct = make([]byte, C.frodo1344_ciphertextbytes)
+
+	ret := C.crypto_kem_enc_Frodo1344(
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&ss[0])),
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to encaps Frodo1344: %d", ret)
+	}
+	return ct, ss, nil
+}
+
+// DecapsFrodo1344 recovers the shared secret from ct using sk.
+func DecapsFrodo1344(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "FrodoKEM-1344", ct, C.frodo1344_ciphertextbytes); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "FrodoKEM-1344", sk, C.frodo1344_secretkeybytes); err != nil {
+		return nil, err
+	}
+	css := make([]byte, C.frodo1344_bytes)
+
+	ret := C.crypto_kem_dec_Frodo1344(
+		(*C.uchar)(unsafe.Pointer(&css[0])),
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("failed to decapsulate Frodo1344: %d", ret)
+	}
+	return css, nil
+}
+
+// KeyPairFrodo1344NIZKPoP generates a FrodoKEM-1344 key pair with its NIZKPoP.
+func KeyPairFrodo1344NIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
+	pk := make([]byte, C.frodo1344_publickeybytes)
+	sk := make([]byte, C.frodo1344_secretkeybytes)
+	var zkpop_c *C.uchar
+	var zkpop_size_c C.ulong
+
+	ret := C.crypto_kem_keypair_nizkpop_Frodo1344(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+		&zkpop_c,
+		&zkpop_size_c,
+	)
+	if ret != 0 {
+		return nil, nil, nil, fmt.Errorf("failed to generate Frodo1344 keypair with NIZKPoP: %d", ret)
+	}
+	zkpopGo := C.GoBytes(unsafe.Pointer(zkpop_c), C.int(zkpop_size_c))
+	C.free(unsafe.Pointer(zkpop_c))
+	return pk, sk, zkpopGo, nil
+}
+
+// frodo1344ProofLimits bounds FrodoKEM-1344 proofs. The frodo-zkpop headers
+// do not export a maximum proof size.
+var frodo1344ProofLimits = proofLimits{
+	pkSize: C.frodo1344_publickeybytes,
+	min:    1,
+}
+
+// VerifyFrodo1344NIZKPoP checks a FrodoKEM-1344 NIZKPoP and returns a
+// *VerifyError explaining why it was rejected, or nil if it is valid.
+func VerifyFrodo1344NIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof("FrodoKEM-1344-NIZKPoP", frodo1344ProofLimits, pk, zkpop, func() int {
+		return int(C.crypto_nizkpop_verify_Frodo1344(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop)),
+		))
+	})
+}
+
+// VerifyFrodo1344ZKPop reports whether zkpop is a valid NIZKPoP for pk.
+func VerifyFrodo1344ZKPop(pk []byte, zkpop []byte) bool {
+	return VerifyFrodo1344NIZKPoP(pk, zkpop) == nil
+}
+
+// Frodo1344 is FrodoKEM-1344, as built in external/KEM-NIZKPoP/frodo-zkpop.
+var Frodo1344 KEM = frodo1344KEM
+
+var frodo1344KEM = &kemScheme{
+	name:     "FrodoKEM-1344",
+	pkSize:   C.frodo1344_publickeybytes,
+	skSize:   C.frodo1344_secretkeybytes,
+	ctSize:   C.frodo1344_ciphertextbytes,
+	ssSize:   C.frodo1344_bytes,
+	keyPair:  KeyPairFrodo1344,
+	seedSize: SeedSize,
+	fromSeed: KeyPairFrodo1344FromSeed,
+	encaps:   EncapsFrodo1344,
+	decaps:   DecapsFrodo1344,
+}
+
+// Frodo1344NIZKPoP is FrodoKEM-1344 with key generation producing a NIZKPoP.
+var Frodo1344NIZKPoP ProvingKEM = frodo1344KEM.withProof("FrodoKEM-1344-NIZKPoP", frodoDefaultProfile, frodo1344ProofLimits, KeyPairFrodo1344NIZKPoP, VerifyFrodo1344NIZKPoP)
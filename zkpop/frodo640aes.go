@@ -0,0 +1,155 @@
+package zkpop
+
+/*
+#cgo LDFLAGS: ${SRCDIR}/../build/frodo/libfrodo640_aes.a
+#include "api_frodo640_aes.h"
+#include <stdint.h>
+#include <stdlib.h>
+enum {
+	frodo640aes_publickeybytes = CRYPTO_PUBLICKEYBYTES,
+	frodo640aes_secretkeybytes = CRYPTO_SECRETKEYBYTES,
+	frodo640aes_ciphertextbytes = CRYPTO_CIPHERTEXTBYTES,
+	frodo640aes_bytes = CRYPTO_BYTES,
+};
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// KeyPairFrodo640AES generates a FrodoKEM-640-AES key pair.
+func KeyPairFrodo640AES() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	pk := make([]byte, C.frodo640aes_publickeybytes)
+	sk := make([]byte, C.frodo640aes_secretkeybytes)
+
+	ret := C.crypto_kem_keypair_Frodo640_AES(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to generate Frodo640AES keypair: %d", ret)
+	}
+	return pk, sk, nil
+}
+
+// EncapsFrodo640AES returns a ciphertext and a 16-byte shared secret for pk.
+func EncapsFrodo640AES(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "FrodoKEM-640-AES", pk, C.frodo640aes_publickeybytes); err != nil {
+		return nil, nil, err
+	}
+	ss = make([]byte, C.frodo640aes_bytes)
+	ct = make([]byte, C.frodo640aes_ciphertextbytes)
+
+	ret := C.crypto_kem_enc_Frodo640_AES(
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&ss[0])),
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to encaps Frodo640AES: %d", ret)
+	}
+	return ct, ss, nil
+}
+
+// DecapsFrodo640AES recovers the shared secret from ct using sk.
+func DecapsFrodo640AES(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "FrodoKEM-640-AES", ct, C.frodo640aes_ciphertextbytes); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "FrodoKEM-640-AES", sk, C.frodo640aes_secretkeybytes); err != nil {
+		return nil, err
+	}
+	css := make([]byte, C.frodo640aes_bytes)
+
+	ret := C.crypto_kem_dec_Frodo640_AES(
+		(*C.uchar)(unsafe.Pointer(&css[0])),
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("failed to decapsulate Frodo640AES: %d", ret)
+	}
+	return css, nil
+}
+
+// KeyPairFrodo640AESNIZKPoP generates a FrodoKEM-640-AES key pair with its
+// NIZKPoP.
+func KeyPairFrodo640AESNIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
+	pk := make([]byte, C.frodo640aes_publickeybytes)
+	sk := make([]byte, C.frodo640aes_secretkeybytes)
+	var zkpop_c *C.uchar
+	var zkpop_size_c C.ulong
+
+	ret := C.crypto_kem_keypair_nizkpop_Frodo640_AES(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+		&zkpop_c,
+		&zkpop_size_c,
+	)
+	if ret != 0 {
+		return nil, nil, nil, fmt.Errorf("failed to generate Frodo640AES keypair with NIZKPoP: %d", ret)
+	}
+	zkpopGo := C.GoBytes(unsafe.Pointer(zkpop_c), C.int(zkpop_size_c))
+	C.free(unsafe.Pointer(zkpop_c))
+	return pk, sk, zkpopGo, nil
+}
+
+// frodo640AESProofLimits bounds FrodoKEM-640-AES proofs. The frodo-zkpop
+// headers do not export a maximum proof size.
+var frodo640AESProofLimits = proofLimits{
+	pkSize: C.frodo640aes_publickeybytes,
+	min:    1,
+}
+
+// VerifyFrodo640AESNIZKPoP checks a FrodoKEM-640-AES NIZKPoP and returns a
+// *VerifyError explaining why it was rejected, or nil if it is valid.
+func VerifyFrodo640AESNIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof("FrodoKEM-640-AES-NIZKPoP", frodo640AESProofLimits, pk, zkpop, func() int {
+		return int(C.crypto_nizkpop_verify_Frodo640_AES(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop)),
+		))
+	})
+}
+
+// VerifyFrodo640AESZKPop reports whether zkpop is a valid NIZKPoP for pk.
+func VerifyFrodo640AESZKPop(pk []byte, zkpop []byte) bool {
+	return VerifyFrodo640AESNIZKPoP(pk, zkpop) == nil
+}
+
+// Frodo640AES is FrodoKEM-640 with the public matrix A generated by AES-128. It
+// links the renamed build produced by scripts/build-frodo-variants.sh, so it
+// can be used alongside Frodo640 and the other variant.
+var Frodo640AES KEM = frodo640AESKEM
+
+var frodo640AESKEM = &kemScheme{
+	name:     "FrodoKEM-640-AES",
+	pkSize:   C.frodo640aes_publickeybytes,
+	skSize:   C.frodo640aes_secretkeybytes,
+	ctSize:   C.frodo640aes_ciphertextbytes,
+	ssSize:   C.frodo640aes_bytes,
+	keyPair:  KeyPairFrodo640AES,
+	seedSize: SeedSize,
+	fromSeed: KeyPairFrodo640AESFromSeed,
+	encaps:   EncapsFrodo640AES,
+	decaps:   DecapsFrodo640AES,
+}
+
+// Frodo640AESNIZKPoP is FrodoKEM-640-AES with key generation producing a
+// NIZKPoP.
+var Frodo640AESNIZKPoP ProvingKEM = frodo640AESKEM.withProof("FrodoKEM-640-AES-NIZKPoP", frodoDefaultProfile, frodo640AESProofLimits, KeyPairFrodo640AESNIZKPoP, VerifyFrodo640AESNIZKPoP)
@@ -0,0 +1,155 @@
+package zkpop
+
+/*
+#cgo LDFLAGS: ${SRCDIR}/../build/frodo/libfrodo640_shake.a
+#include "api_frodo640_shake.h"
+#include <stdint.h>
+#include <stdlib.h>
+enum {
+	frodo640shake_publickeybytes = CRYPTO_PUBLICKEYBYTES,
+	frodo640shake_secretkeybytes = CRYPTO_SECRETKEYBYTES,
+	frodo640shake_ciphertextbytes = CRYPTO_CIPHERTEXTBYTES,
+	frodo640shake_bytes = CRYPTO_BYTES,
+};
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// KeyPairFrodo640SHAKE generates a FrodoKEM-640-SHAKE key pair.
+func KeyPairFrodo640SHAKE() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	pk := make([]byte, C.frodo640shake_publickeybytes)
+	sk := make([]byte, C.frodo640shake_secretkeybytes)
+
+	ret := C.crypto_kem_keypair_Frodo640_SHAKE(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to generate Frodo640SHAKE keypair: %d", ret)
+	}
+	return pk, sk, nil
+}
+
+// EncapsFrodo640SHAKE returns a ciphertext and a 16-byte shared secret for pk.
+func EncapsFrodo640SHAKE(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "FrodoKEM-640-SHAKE", pk, C.frodo640shake_publickeybytes); err != nil {
+		return nil, nil, err
+	}
+	ss = make([]byte, C.frodo640shake_bytes)
+	ct = make([]byte, C.frodo640shake_ciphertextbytes)
+
+	ret := C.crypto_kem_enc_Frodo640_SHAKE(
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&ss[0])),
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to encaps Frodo640SHAKE: %d", ret)
+	}
+	return ct, ss, nil
+}
+
+// DecapsFrodo640SHAKE recovers the shared secret from ct using sk.
+func DecapsFrodo640SHAKE(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "FrodoKEM-640-SHAKE", ct, C.frodo640shake_ciphertextbytes); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "FrodoKEM-640-SHAKE", sk, C.frodo640shake_secretkeybytes); err != nil {
+		return nil, err
+	}
+	css := make([]byte, C.frodo640shake_bytes)
+
+	ret := C.crypto_kem_dec_Frodo640_SHAKE(
+		(*C.uchar)(unsafe.Pointer(&css[0])),
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("failed to decapsulate Frodo640SHAKE: %d", ret)
+	}
+	return css, nil
+}
+
+// KeyPairFrodo640SHAKENIZKPoP generates a FrodoKEM-640-SHAKE key pair with its
+// NIZKPoP.
+func KeyPairFrodo640SHAKENIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
+	pk := make([]byte, C.frodo640shake_publickeybytes)
+	sk := make([]byte, C.frodo640shake_secretkeybytes)
+	var zkpop_c *C.uchar
+	var zkpop_size_c C.ulong
+
+	ret := C.crypto_kem_keypair_nizkpop_Frodo640_SHAKE(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+		&zkpop_c,
+		&zkpop_size_c,
+	)
+	if ret != 0 {
+		return nil, nil, nil, fmt.Errorf("failed to generate Frodo640SHAKE keypair with NIZKPoP: %d", ret)
+	}
+	zkpopGo := C.GoBytes(unsafe.Pointer(zkpop_c), C.int(zkpop_size_c))
+	C.free(unsafe.Pointer(zkpop_c))
+	return pk, sk, zkpopGo, nil
+}
+
+// frodo640SHAKEProofLimits bounds FrodoKEM-640-SHAKE proofs. The frodo-zkpop
+// headers do not export a maximum proof size.
+var frodo640SHAKEProofLimits = proofLimits{
+	pkSize: C.frodo640shake_publickeybytes,
+	min:    1,
+}
+
+// VerifyFrodo640SHAKENIZKPoP checks a FrodoKEM-640-SHAKE NIZKPoP and returns a
+// *VerifyError explaining why it was rejected, or nil if it is valid.
+func VerifyFrodo640SHAKENIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof("FrodoKEM-640-SHAKE-NIZKPoP", frodo640SHAKEProofLimits, pk, zkpop, func() int {
+		return int(C.crypto_nizkpop_verify_Frodo640_SHAKE(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop)),
+		))
+	})
+}
+
+// VerifyFrodo640SHAKEZKPop reports whether zkpop is a valid NIZKPoP for pk.
+func VerifyFrodo640SHAKEZKPop(pk []byte, zkpop []byte) bool {
+	return VerifyFrodo640SHAKENIZKPoP(pk, zkpop) == nil
+}
+
+// Frodo640SHAKE is FrodoKEM-640 with the public matrix A generated by SHAKE-128. It
+// links the renamed build produced by scripts/build-frodo-variants.sh, so it
+// can be used alongside Frodo640 and the other variant.
+var Frodo640SHAKE KEM = frodo640SHAKEKEM
+
+var frodo640SHAKEKEM = &kemScheme{
+	name:     "FrodoKEM-640-SHAKE",
+	pkSize:   C.frodo640shake_publickeybytes,
+	skSize:   C.frodo640shake_secretkeybytes,
+	ctSize:   C.frodo640shake_ciphertextbytes,
+	ssSize:   C.frodo640shake_bytes,
+	keyPair:  KeyPairFrodo640SHAKE,
+	seedSize: SeedSize,
+	fromSeed: KeyPairFrodo640SHAKEFromSeed,
+	encaps:   EncapsFrodo640SHAKE,
+	decaps:   DecapsFrodo640SHAKE,
+}
+
+// Frodo640SHAKENIZKPoP is FrodoKEM-640-SHAKE with key generation producing a
+// NIZKPoP.
+var Frodo640SHAKENIZKPoP ProvingKEM = frodo640SHAKEKEM.withProof("FrodoKEM-640-SHAKE-NIZKPoP", frodoDefaultProfile, frodo640SHAKEProofLimits, KeyPairFrodo640SHAKENIZKPoP, VerifyFrodo640SHAKENIZKPoP)
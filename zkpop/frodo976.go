@@ -0,0 +1,151 @@
+package zkpop
+
+/*
+#cgo LDFLAGS: ${SRCDIR}/../external/KEM-NIZKPoP/frodo-zkpop/frodo976/libfrodo.a
+#include "api_frodo976.h"
+#include <stdint.h>
+#include <stdlib.h>
+enum {
+	frodo976_publickeybytes = CRYPTO_PUBLICKEYBYTES,
+	frodo976_secretkeybytes = CRYPTO_SECRETKEYBYTES,
+	frodo976_ciphertextbytes = CRYPTO_CIPHERTEXTBYTES,
+	frodo976_bytes = CRYPTO_BYTES,
+};
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// KeyPairFrodo976 generates a FrodoKEM-976 key pair.
+func KeyPairFrodo976() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	pk := make([]byte, C.frodo976_publickeybytes)
+	sk := make([]byte, C.frodo976_secretkeybytes)
+
+	ret := C.crypto_kem_keypair_Frodo976(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to generate Frodo976 keypair: %d", ret)
+	}
+	return pk, sk, nil
+}
+
+// EncapsFrodo976 returns a ciphertext and a 24-byte shared secret for pk.
+func EncapsFrodo976(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "FrodoKEM-976", pk, C.frodo976_publickeybytes); err != nil {
+		return nil, nil, err
+	}
+	ss = make([]byte, C.frodo976_bytes)
+	ct = make([]byte, C.frodo976_ciphertextbytes)
+
+	ret := C.crypto_kem_enc_Frodo976(
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&ss[0])),
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to encaps Frodo976: %d", ret)
+	}
+	return ct, ss, nil
+}
+
+// DecapsFrodo976 recovers the shared secret from ct using sk.
+func DecapsFrodo976(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "FrodoKEM-976", ct, C.frodo976_ciphertextbytes); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "FrodoKEM-976", sk, C.frodo976_secretkeybytes); err != nil {
+		return nil, err
+	}
+	css := make([]byte, C.frodo976_bytes)
+
+	ret := C.crypto_kem_dec_Frodo976(
+		(*C.uchar)(unsafe.Pointer(&css[0])),
+		(*C.uchar)(unsafe.Pointer(&ct[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("failed to decapsulate Frodo976: %d", ret)
+	}
+	return css, nil
+}
+
+// KeyPairFrodo976NIZKPoP generates a FrodoKEM-976 key pair with its NIZKPoP.
+func KeyPairFrodo976NIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
+	pk := make([]byte, C.frodo976_publickeybytes)
+	sk := make([]byte, C.frodo976_secretkeybytes)
+	var zkpop_c *C.uchar
+	var zkpop_size_c C.ulong
+
+	ret := C.crypto_kem_keypair_nizkpop_Frodo976(
+		(*C.uchar)(unsafe.Pointer(&pk[0])),
+		(*C.uchar)(unsafe.Pointer(&sk[0])),
+		&zkpop_c,
+		&zkpop_size_c,
+	)
+	if ret != 0 {
+		return nil, nil, nil, fmt.Errorf("failed to generate Frodo976 keypair with NIZKPoP: %d", ret)
+	}
+	zkpopGo := C.GoBytes(unsafe.Pointer(zkpop_c), C.int(zkpop_size_c))
+	C.free(unsafe.Pointer(zkpop_c))
+	return pk, sk, zkpopGo, nil
+}
+
+// frodo976ProofLimits bounds FrodoKEM-976 proofs. The frodo-zkpop headers
+// do not export a maximum proof size.
+var frodo976ProofLimits = proofLimits{
+	pkSize: C.frodo976_publickeybytes,
+	min:    1,
+}
+
+// VerifyFrodo976NIZKPoP checks a FrodoKEM-976 NIZKPoP and returns a
+// *VerifyError explaining why it was rejected, or nil if it is valid.
+func VerifyFrodo976NIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof("FrodoKEM-976-NIZKPoP", frodo976ProofLimits, pk, zkpop, func() int {
+		return int(C.crypto_nizkpop_verify_Frodo976(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop)),
+		))
+	})
+}
+
+// VerifyFrodo976ZKPop reports whether zkpop is a valid NIZKPoP for pk.
+func VerifyFrodo976ZKPop(pk []byte, zkpop []byte) bool {
+	return VerifyFrodo976NIZKPoP(pk, zkpop) == nil
+}
+
+// Frodo976 is FrodoKEM-976, as built in external/KEM-NIZKPoP/frodo-zkpop.
+var Frodo976 KEM = frodo976KEM
+
+var frodo976KEM = &kemScheme{
+	name:     "FrodoKEM-976",
+	pkSize:   C.frodo976_publickeybytes,
+	skSize:   C.frodo976_secretkeybytes,
+	ctSize:   C.frodo976_ciphertextbytes,
+	ssSize:   C.frodo976_bytes,
+	keyPair:  KeyPairFrodo976,
+	seedSize: SeedSize,
+	fromSeed: KeyPairFrodo976FromSeed,
+	encaps:   EncapsFrodo976,
+	decaps:   DecapsFrodo976,
+}
+
+// Frodo976NIZKPoP is FrodoKEM-976 with key generation producing a NIZKPoP.
+var Frodo976NIZKPoP ProvingKEM = frodo976KEM.withProof("FrodoKEM-976-NIZKPoP", frodoDefaultProfile, frodo976ProofLimits, KeyPairFrodo976NIZKPoP, VerifyFrodo976NIZKPoP)
@@ -2,6 +2,7 @@
 package zkpop
 
 /*
+#cgo LDFLAGS: ${SRCDIR}/../external/KEM-NIZKPoP/frodo-zkpop/frodo640/libfrodo.a -lssl -lcrypto
 #include "api_frodo640.h"
 #include <stdint.h>
 #include <stdlib.h>
@@ -17,6 +18,9 @@ import (
 
 //binding for crypto_kem_keypair_Frodo640
 func KeyPairFrodo640()([]byte, []byte, error){
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
 	pk := make([]byte, C.CRYPTO_PUBLICKEYBYTES)
         sk := make([]byte, C.CRYPTO_SECRETKEYBYTES)
 
@@ -32,6 +36,12 @@ func KeyPairFrodo640()([]byte, []byte, error){
 //Encapsulation for a given public key pk
 //Returns a ciphertext ct and a 16-byte shared secret ss
 func EncapsFrodo640(pk []byte)([]byte, []byte, error){
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, C.CRYPTO_ALGNAME, pk, C.CRYPTO_PUBLICKEYBYTES); err != nil {
+		return nil, nil, err
+	}
 //	crypto_kem_enc_Frodo640
 //	(unsigned char *ct, unsigned char *ss, const unsigned char *pk)
 	ss := make([]byte, C.CRYPTO_BYTES)
@@ -50,6 +60,15 @@ func EncapsFrodo640(pk []byte)([]byte, []byte, error){
 //Given a ciphertext ct and a private key sk
 //returns a candidate shared secret css
 func DecapsFrodo640(ct []byte, sk []byte)([]byte, error){
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, C.CRYPTO_ALGNAME, ct, C.CRYPTO_CIPHERTEXTBYTES); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, C.CRYPTO_ALGNAME, sk, C.CRYPTO_SECRETKEYBYTES); err != nil {
+		return nil, err
+	}
 	//crypto_kem_dec_Frodo640
 	css := make([]byte, C.CRYPTO_BYTES)
 
@@ -61,3 +80,19 @@ func DecapsFrodo640(ct []byte, sk []byte)([]byte, error){
         }
         return css, nil
 }
+
+// Frodo640 is FrodoKEM-640, as built in external/KEM-NIZKPoP/frodo-zkpop.
+var Frodo640 KEM = frodo640KEM
+
+var frodo640KEM = &kemScheme{
+	name:     C.CRYPTO_ALGNAME,
+	pkSize:   C.CRYPTO_PUBLICKEYBYTES,
+	skSize:   C.CRYPTO_SECRETKEYBYTES,
+	ctSize:   C.CRYPTO_CIPHERTEXTBYTES,
+	ssSize:   C.CRYPTO_BYTES,
+	keyPair:  KeyPairFrodo640,
+	seedSize: SeedSize,
+	fromSeed: KeyPairFrodo640FromSeed,
+	encaps:   EncapsFrodo640,
+	decaps:   DecapsFrodo640,
+}
@@ -0,0 +1,90 @@
+// Package drbg implements the AES256_CTR_DRBG of rng.c in the NIST PQC
+// submission packages: CTR_DRBG with AES-256, no derivation function and no
+// prediction resistance.
+//
+// It reproduces byte for byte what PQCgenKAT_kem draws from randombytes, so
+// the KAT generators and the deterministic key generation in package zkpop
+// agree with the published Known Answer Tests.
+package drbg
+
+import (
+	"crypto/aes"
+	"errors"
+)
+
+// SeedSize is the size of the entropy input and of the optional
+// personalization string.
+const SeedSize = 48
+
+// Reader is a seeded AES256_CTR_DRBG. Each Read is one call to the C
+// randombytes: it fills p and then updates the state, so splitting a read
+// into two changes every later output. It is not safe for concurrent use.
+type Reader struct {
+	key [32]byte
+	v   [16]byte
+}
+
+// New returns a DRBG initialised like randombytes_init(entropy,
+// personalization, 256). personalization may be nil.
+func New(entropy, personalization []byte) (*Reader, error) {
+	if len(entropy) != SeedSize {
+		return nil, errors.New("drbg: entropy input must be 48 bytes")
+	}
+	if personalization != nil && len(personalization) != SeedSize {
+		return nil, errors.New("drbg: personalization string must be 48 bytes")
+	}
+	var seed [SeedSize]byte
+	copy(seed[:], entropy)
+	for i, b := range personalization {
+		seed[i] ^= b
+	}
+	r := new(Reader)
+	r.update(&seed)
+	return r, nil
+}
+
+// Read fills p and always succeeds.
+func (r *Reader) Read(p []byte) (int, error) {
+	var block [aes.BlockSize]byte
+	c, err := aes.NewCipher(r.key[:])
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < len(p); i += aes.BlockSize {
+		r.increment()
+		c.Encrypt(block[:], r.v[:])
+		copy(p[i:], block[:])
+	}
+	r.update(nil)
+	return len(p), nil
+}
+
+// update is AES256_CTR_DRBG_Update.
+func (r *Reader) update(provided *[SeedSize]byte) {
+	var temp [SeedSize]byte
+	c, err := aes.NewCipher(r.key[:])
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < SeedSize; i += aes.BlockSize {
+		r.increment()
+		c.Encrypt(temp[i:], r.v[:])
+	}
+	if provided != nil {
+		for i := range temp {
+			temp[i] ^= provided[i]
+		}
+	}
+	copy(r.key[:], temp[:32])
+	copy(r.v[:], temp[32:])
+}
+
+// increment adds one to V as a big-endian counter.
+func (r *Reader) increment() {
+	for j := len(r.v) - 1; j >= 0; j-- {
+		r.v[j]++
+		if r.v[j] != 0 {
+			break
+		}
+	}
+}
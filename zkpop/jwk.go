@@ -0,0 +1,112 @@
+package zkpop
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON Web Key representation of public keys, following the "AKP"
+// (algorithm key pair) key type:
+//
+//	{
+//	  "kty": "AKP",
+//	  "alg": "Kyber768",
+//	  "pub": "<base64url public key>",
+//	  "nizkpop": "<base64url Envelope>"
+//	}
+//
+// alg is the name of the plain KEM, as returned by Lookup. The optional
+// nizkpop member carries the binary encoding of an Envelope for the key.
+
+const jwkKeyType = "AKP"
+
+// ErrInvalidJWK is returned by ParseJWK for JSON that is not a JWK this
+// package can use.
+var ErrInvalidJWK = errors.New("invalid JWK")
+
+type jwk struct {
+	Kty     string `json:"kty"`
+	Alg     string `json:"alg"`
+	Pub     string `json:"pub"`
+	Priv    string `json:"priv,omitempty"`
+	NIZKPoP string `json:"nizkpop,omitempty"`
+}
+
+// MarshalJWK encodes pk as a JWK. If e is not nil it is attached as the
+// nizkpop member; it must be an envelope for pk, made by the proving
+// scheme of pk's KEM, or an error is returned.
+func MarshalJWK(pk *PublicKey, e *Envelope) ([]byte, error) {
+	k := jwk{
+		Kty: jwkKeyType,
+		Alg: keyScheme(pk.scheme).Name(),
+		Pub: base64.RawURLEncoding.EncodeToString(pk.b),
+	}
+	if e != nil {
+		if keyScheme(e.scheme) != keyScheme(pk.scheme) {
+			return nil, fmt.Errorf("%s proof attached to a %s key", e.scheme.Name(), pk.scheme.Name())
+		}
+		if sha256.Sum256(pk.b) != e.pkHash {
+			return nil, ErrKeyMismatch
+		}
+		b, err := e.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		k.NIZKPoP = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return json.Marshal(k)
+}
+
+// ParseJWK decodes a public key JWK. If the JWK carries a nizkpop member,
+// the proof is verified against the key and the key is only returned if it
+// is accepted; the envelope is returned alongside. Callers that require a
+// proof must check that the returned envelope is not nil.
+func ParseJWK(data []byte) (*PublicKey, *Envelope, error) {
+	var k jwk
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidJWK, err)
+	}
+	if k.Kty != jwkKeyType {
+		return nil, nil, fmt.Errorf("%w: kty %q, want %q", ErrInvalidJWK, k.Kty, jwkKeyType)
+	}
+	if k.Priv != "" {
+		return nil, nil, fmt.Errorf("%w: unexpected private key", ErrInvalidJWK)
+	}
+	// alg values are case-sensitive in JOSE, unlike names passed to Lookup.
+	scheme, err := Lookup(k.Alg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scheme.Name() != k.Alg {
+		return nil, nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, k.Alg)
+	}
+	if _, ok := scheme.(ProvingKEM); ok {
+		return nil, nil, fmt.Errorf("%w: alg %q identifies a proof, not a key", ErrInvalidJWK, k.Alg)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(k.Pub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: pub: %v", ErrInvalidJWK, err)
+	}
+	pk, err := NewPublicKey(scheme, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if k.NIZKPoP == "" {
+		return pk, nil, nil
+	}
+	b, err = base64.RawURLEncoding.DecodeString(k.NIZKPoP)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: nizkpop: %v", ErrInvalidJWK, err)
+	}
+	var e Envelope
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, nil, err
+	}
+	if err := e.Verify(pk); err != nil {
+		return nil, nil, err
+	}
+	return pk, &e, nil
+}
@@ -0,0 +1,197 @@
+// Package kat reproduces the NIST Known Answer Tests of the KEMs bound from
+// C and checks them against the published responses.
+//
+// It follows PQCgenKAT_kem from the submission packages: a master
+// AES256_CTR_DRBG seeded with the bytes 0, 1, ..., 47 yields one 48-byte
+// seed per test; each test reseeds the DRBG with its seed, then runs key
+// generation, encapsulation and decapsulation with randombytes drawing from
+// it. Request and response files use the same text format, so a response
+// written here is byte-identical to the one the C generator writes for the
+// same library, apart from the "#" header line.
+//
+// A mismatch means the linked C library does not compute what upstream
+// does: a wrong build, a wrong parameter set or a miscompilation.
+package kat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"zkpop-go/zkpop"
+	"zkpop-go/zkpop/internal/drbg"
+)
+
+// DefaultCount is the number of tests in the upstream KAT files.
+const DefaultCount = 100
+
+// Entry is one test of a request or response file. In a request only Count
+// and Seed are set.
+type Entry struct {
+	Count int
+	Seed  []byte
+	PK    []byte
+	SK    []byte
+	CT    []byte
+	SS    []byte
+}
+
+// Seeds returns the first n test seeds, as PQCgenKAT_kem derives them.
+func Seeds(n int) [][]byte {
+	entropy := make([]byte, drbg.SeedSize)
+	for i := range entropy {
+		entropy[i] = byte(i)
+	}
+	r, err := drbg.New(entropy, nil)
+	if err != nil {
+		panic(err)
+	}
+	seeds := make([][]byte, n)
+	for i := range seeds {
+		seeds[i] = make([]byte, drbg.SeedSize)
+		r.Read(seeds[i])
+	}
+	return seeds
+}
+
+// FileName returns the name PQCgenKAT_kem gives the files of k, without the
+// .req or .rsp extension.
+func FileName(k zkpop.KEM) string {
+	return fmt.Sprintf("PQCkemKAT_%d", k.PrivateKeySize())
+}
+
+// Generate runs test count of k with the given seed and checks that
+// decapsulation recovers the shared secret.
+func Generate(k zkpop.KEM, count int, seed []byte) (*Entry, error) {
+	r, err := drbg.New(seed, nil)
+	if err != nil {
+		return nil, err
+	}
+	s := zkpop.WithRand(k, r)
+	pk, sk, err := s.GenerateKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("count %d: %w", count, err)
+	}
+	ct, ss, err := s.Encapsulate(pk)
+	if err != nil {
+		return nil, fmt.Errorf("count %d: %w", count, err)
+	}
+	ss1, err := k.Decapsulate(ct, sk)
+	if err != nil {
+		return nil, fmt.Errorf("count %d: %w", count, err)
+	}
+	if !bytes.Equal(ss, ss1) {
+		return nil, fmt.Errorf("count %d: decapsulation returned a different shared secret", count)
+	}
+	return &Entry{Count: count, Seed: bytes.Clone(seed), PK: pk, SK: sk, CT: ct, SS: ss}, nil
+}
+
+// WriteRequest writes a request file for the given seeds.
+func WriteRequest(w io.Writer, seeds [][]byte) error {
+	bw := bufio.NewWriter(w)
+	for i, seed := range seeds {
+		fmt.Fprintf(bw, "count = %d\n", i)
+		fmt.Fprintf(bw, "seed = %X\n", seed)
+		fmt.Fprintf(bw, "pk =\nsk =\nct =\nss =\n\n")
+	}
+	return bw.Flush()
+}
+
+// WriteResponseHeader writes the "#" line that opens a response file.
+func WriteResponseHeader(w io.Writer, name string) error {
+	_, err := fmt.Fprintf(w, "# %s\n\n", name)
+	return err
+}
+
+// WriteEntry appends one test to a response file.
+func WriteEntry(w io.Writer, e *Entry) error {
+	_, err := fmt.Fprintf(w, "count = %d\nseed = %X\npk = %X\nsk = %X\nct = %X\nss = %X\n\n",
+		e.Count, e.Seed, e.PK, e.SK, e.CT, e.SS)
+	return err
+}
+
+// Read parses a request or response file. Comment lines are skipped.
+func Read(r io.Reader) ([]*Entry, error) {
+	var entries []*Entry
+	var e *Entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "count" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid count: %w", line, err)
+			}
+			e = &Entry{Count: n}
+			entries = append(entries, e)
+			continue
+		}
+		if e == nil {
+			return nil, fmt.Errorf("line %d: %s before the first count", line, key)
+		}
+		b, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", line, key, err)
+		}
+		switch key {
+		case "seed":
+			e.Seed = b
+		case "pk":
+			e.PK = b
+		case "sk":
+			e.SK = b
+		case "ct":
+			e.CT = b
+		case "ss":
+			e.SS = b
+		default:
+			return nil, fmt.Errorf("line %d: unknown field %q", line, key)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ErrMismatch is returned by Compare when a test differs from the expected
+// response.
+var ErrMismatch = errors.New("KAT mismatch")
+
+// Compare checks a generated test against the expected one and names the
+// first field that differs.
+func Compare(got, want *Entry) error {
+	fields := []struct {
+		name      string
+		got, want []byte
+	}{
+		{"seed", got.Seed, want.Seed},
+		{"pk", got.PK, want.PK},
+		{"sk", got.SK, want.SK},
+		{"ct", got.CT, want.CT},
+		{"ss", got.SS, want.SS},
+	}
+	if got.Count != want.Count {
+		return fmt.Errorf("%w: count %d, expected count %d", ErrMismatch, got.Count, want.Count)
+	}
+	for _, f := range fields {
+		if !bytes.Equal(f.got, f.want) {
+			return fmt.Errorf("%w: count %d: %s differs", ErrMismatch, got.Count, f.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package zkpop
+
+import (
+	"fmt"
+	"io"
+)
+
+// KEM is a key encapsulation mechanism bound from the KEM-NIZKPoP C library.
+// Implementations are stateless and safe for concurrent use.
+type KEM interface {
+	// Name returns the algorithm name as reported by CRYPTO_ALGNAME.
+	Name() string
+
+	// GenerateKeyPair returns a fresh public and private key.
+	GenerateKeyPair() (pk, sk []byte, err error)
+
+	// GenerateKeyPairFromSeed derives a key pair from a seed of SeedSize
+	// bytes. The same seed always gives the same keys.
+	GenerateKeyPairFromSeed(seed []byte) (pk, sk []byte, err error)
+
+	// Encapsulate returns a ciphertext and a shared secret for pk.
+	Encapsulate(pk []byte) (ct, ss []byte, err error)
+
+	// Decapsulate recovers the shared secret from ct using sk.
+	Decapsulate(ct, sk []byte) (ss []byte, err error)
+
+	PublicKeySize() int
+	PrivateKeySize() int
+	CiphertextSize() int
+	SharedSecretSize() int
+	SeedSize() int
+}
+
+// kemScheme implements KEM on top of the per-scheme cgo bindings.
+type kemScheme struct {
+	name     string
+	pkSize   int
+	skSize   int
+	ctSize   int
+	ssSize   int
+	keyPair  func() ([]byte, []byte, error)
+	seedSize int
+	fromSeed func(seed []byte) ([]byte, []byte, error)
+	encaps   func(pk []byte) ([]byte, []byte, error)
+	decaps   func(ct, sk []byte) ([]byte, error)
+
+	// native is set for schemes implemented in Go, which never call
+	// randombytes.
+	native bool
+
+	// rand and orig are set on copies made by WithRand: the source of
+	// randomness and the scheme it was copied from.
+	rand io.Reader
+	orig KEM
+}
+
+func (s *kemScheme) Name() string { return s.name }
+
+func (s *kemScheme) GenerateKeyPair() ([]byte, []byte, error) {
+	if s.native && s.rand != nil {
+		seed := make([]byte, s.seedSize)
+		if _, err := io.ReadFull(s.rand, seed); err != nil {
+			return nil, nil, fmt.Errorf("failed to read randomness: %w", err)
+		}
+		return s.fromSeed(seed)
+	}
+	var pk, sk []byte
+	err := s.withRand(func() (err error) {
+		pk, sk, err = s.keyPair()
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, sk, nil
+}
+
+func (s *kemScheme) GenerateKeyPairFromSeed(seed []byte) ([]byte, []byte, error) {
+	return s.fromSeed(seed)
+}
+
+func (s *kemScheme) Encapsulate(pk []byte) ([]byte, []byte, error) {
+	var ct, ss []byte
+	err := s.withRand(func() (err error) {
+		ct, ss, err = s.encaps(pk)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ct, ss, nil
+}
+
+func (s *kemScheme) Decapsulate(ct, sk []byte) ([]byte, error) {
+	return s.decaps(ct, sk)
+}
+
+func (s *kemScheme) PublicKeySize() int    { return s.pkSize }
+func (s *kemScheme) PrivateKeySize() int   { return s.skSize }
+func (s *kemScheme) CiphertextSize() int   { return s.ctSize }
+func (s *kemScheme) SharedSecretSize() int { return s.ssSize }
+func (s *kemScheme) SeedSize() int         { return s.seedSize }
+
+// withRand runs f with randombytes reading from the source set by WithRand,
+// if any.
+func (s *kemScheme) withRand(f func() error) error {
+	if s.rand == nil {
+		return f()
+	}
+	return withRand(s.rand, f)
+}
@@ -0,0 +1,135 @@
+package zkpop
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// PublicKey is a KEM public key tagged with the scheme it belongs to.
+type PublicKey struct {
+	scheme KEM
+	b      []byte
+}
+
+// PrivateKey is a KEM private key tagged with the scheme it belongs to.
+type PrivateKey struct {
+	scheme KEM
+	b      []byte
+}
+
+// Proof is a NIZKPoP tagged with the scheme that produced it.
+type Proof struct {
+	scheme ProvingKEM
+	b      []byte
+}
+
+// NewPublicKey checks that b is a public key of the right size for scheme
+// and wraps a copy of it.
+func NewPublicKey(scheme KEM, b []byte) (*PublicKey, error) {
+	if err := checkSize(ErrInvalidPublicKeySize, scheme.Name(), b, scheme.PublicKeySize()); err != nil {
+		return nil, err
+	}
+	return &PublicKey{scheme, bytes.Clone(b)}, nil
+}
+
+// NewPrivateKey checks that b is a private key of the right size for scheme
+// and wraps a copy of it.
+func NewPrivateKey(scheme KEM, b []byte) (*PrivateKey, error) {
+	if err := checkSize(ErrInvalidPrivateKeySize, scheme.Name(), b, scheme.PrivateKeySize()); err != nil {
+		return nil, err
+	}
+	return &PrivateKey{scheme, bytes.Clone(b)}, nil
+}
+
+// NewProof wraps a copy of a proof produced by scheme.
+func NewProof(scheme ProvingKEM, b []byte) (*Proof, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyProof, scheme.Name())
+	}
+	return &Proof{scheme, bytes.Clone(b)}, nil
+}
+
+// GenerateKey returns a fresh key pair for scheme.
+func GenerateKey(scheme KEM) (*PublicKey, *PrivateKey, error) {
+	pk, sk, err := scheme.GenerateKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &PublicKey{scheme, pk}, &PrivateKey{scheme, sk}, nil
+}
+
+// GenerateKeyWithProof returns a fresh key pair for scheme together with its
+// proof of possession.
+func GenerateKeyWithProof(scheme ProvingKEM) (*PublicKey, *PrivateKey, *Proof, error) {
+	pk, sk, proof, err := scheme.GenerateKeyPairWithProof()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return &PublicKey{scheme, pk}, &PrivateKey{scheme, sk}, &Proof{scheme, proof}, nil
+}
+
+// Bytes returns a copy of the encoded public key.
+func (k *PublicKey) Bytes() []byte { return bytes.Clone(k.b) }
+
+// Scheme returns the scheme the key belongs to.
+func (k *PublicKey) Scheme() KEM { return k.scheme }
+
+// Equal reports whether x is a public key of the same scheme with the same
+// encoding.
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return keyScheme(k.scheme) == keyScheme(xx.scheme) && bytes.Equal(k.b, xx.b)
+}
+
+// Encapsulate returns a ciphertext and shared secret for k.
+func (k *PublicKey) Encapsulate() (ct, ss []byte, err error) {
+	return k.scheme.Encapsulate(k.b)
+}
+
+// Bytes returns a copy of the encoded private key.
+func (k *PrivateKey) Bytes() []byte { return bytes.Clone(k.b) }
+
+// Scheme returns the scheme the key belongs to.
+func (k *PrivateKey) Scheme() KEM { return k.scheme }
+
+// Equal reports whether x is a private key of the same scheme with the same
+// encoding. The comparison of key material is constant time.
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	xx, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+	return keyScheme(k.scheme) == keyScheme(xx.scheme) && subtle.ConstantTimeCompare(k.b, xx.b) == 1
+}
+
+// Decapsulate recovers the shared secret from ct.
+func (k *PrivateKey) Decapsulate(ct []byte) (ss []byte, err error) {
+	return k.scheme.Decapsulate(ct, k.b)
+}
+
+// Bytes returns a copy of the encoded proof.
+func (p *Proof) Bytes() []byte { return bytes.Clone(p.b) }
+
+// Scheme returns the scheme that produced the proof.
+func (p *Proof) Scheme() ProvingKEM { return p.scheme }
+
+// Equal reports whether x is the same proof for the same scheme.
+func (p *Proof) Equal(x *Proof) bool {
+	return x != nil && registered(p.scheme) == registered(x.scheme) && bytes.Equal(p.b, x.b)
+}
+
+// Verify checks the proof against pk. Keys of a different scheme are
+// rejected without calling into the C verifier.
+func (p *Proof) Verify(pk *PublicKey) error {
+	if keyScheme(pk.scheme) != keyScheme(p.scheme) {
+		return &VerifyError{Scheme: p.scheme.Name(), Reason: ReasonWrongParameterSet,
+			Err: errors.New("public key belongs to " + pk.scheme.Name())}
+	}
+	return p.scheme.VerifyProof(pk.b, p.b)
+}
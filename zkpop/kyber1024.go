@@ -1,15 +1,21 @@
 package zkpop
 
 /*
-#cgo LDFLAGS: -lpqcrystals_kyber1024_avx2
 #define KYBER_K 4
 #include "kyber/params.h"
 #include "kyber/api_kyber.h"
 #include "kyber/api_kyber_zkpop.h"
 #include <stdint.h>
+#include "kyber_backend.h"
 #include <stdlib.h>
-int pqcrystals_kyber1024_avx2_crypto_kem_keypair_nizkpop(uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size);
-int pqcrystals_kyber1024_avx2_crypto_nizkpop_verify(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size);
+ZKPOP_DECLARE_KYBER_NIZKPOP(1024)
+enum {
+	kyber1024_zkpop_n = ZKPOP_N,
+	kyber1024_zkpop_tau = ZKPOP_TAU,
+	kyber1024_zkpop_symbytes = ZKPOP_SYMBYTES,
+	kyber1024_zkpop_m_packedbytes = ZKPOP_M_PACKEDBYTES,
+	kyber1024_zkpop_m_sigma_packedbytes = ZKPOP_M_SIGMA_PACKEDBYTES,
+};
 */
 import "C"
 import (
@@ -18,11 +24,14 @@ import (
 )
 
 func KeyPairKyber1024NIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
 	pk := make([]byte, C.pqcrystals_kyber1024_PUBLICKEYBYTES)
 	sk := make([]byte, C.pqcrystals_kyber1024_SECRETKEYBYTES)
 	var zkpop_c *C.uint8_t
 	var zkpop_size_c C.size_t
-	ret := C.pqcrystals_kyber1024_avx2_crypto_kem_keypair_nizkpop(
+	ret := C.kyber1024_keypair_nizkpop(
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
 		&zkpop_c,
@@ -36,11 +45,32 @@ func KeyPairKyber1024NIZKPoP() ([]byte, []byte, []byte, error) {
 	return pk, sk, zkpopGo, nil
 }
 
+var kyber1024ProofShape = kyberProofShape{
+	pkSize:       C.pqcrystals_kyber1024_PUBLICKEYBYTES,
+	symBytes:     C.kyber1024_zkpop_symbytes,
+	mPacked:      C.kyber1024_zkpop_m_packedbytes,
+	mSigmaPacked: C.kyber1024_zkpop_m_sigma_packedbytes,
+}
+
+// kyber1024DefaultProfile is the profile set in kyber/params.h.
+var kyber1024DefaultProfile = Profile{ID: 0, N: C.kyber1024_zkpop_n, Tau: C.kyber1024_zkpop_tau}
+
+// VerifyKyber1024NIZKPoP checks a Kyber1024 NIZKPoP and returns a *VerifyError
+// explaining why it was rejected, or nil if it is valid.
+func VerifyKyber1024NIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof("Kyber1024-NIZKPoP", kyber1024ProofShape.limits(kyber1024DefaultProfile), pk, zkpop, func() int {
+		return int(C.kyber1024_nizkpop_verify(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop)),
+		))
+	})
+}
+
+// VerifyKyber1024ZKPop reports whether zkpop is a valid NIZKPoP for pk.
 func VerifyKyber1024ZKPop(pk []byte, zkpop []byte) bool {
-	ret := C.pqcrystals_kyber1024_avx2_crypto_nizkpop_verify(
-		(*C.uchar)(unsafe.Pointer(&pk[0])),
-		(*C.uchar)(unsafe.Pointer(&zkpop[0])),
-		C.ulong(len(zkpop)),
-	)
-	return ret == 0
+	return VerifyKyber1024NIZKPoP(pk, zkpop) == nil
 }
+
+// Kyber1024NIZKPoP is Kyber1024 with key generation producing a NIZKPoP.
+var Kyber1024NIZKPoP ProvingKEM = kyber1024KEM.withProof("Kyber1024-NIZKPoP", kyber1024DefaultProfile, kyber1024ProofShape.limits(kyber1024DefaultProfile), KeyPairKyber1024NIZKPoP, VerifyKyber1024NIZKPoP)
@@ -1,15 +1,21 @@
 package zkpop
 
 /*
-#cgo LDFLAGS: -lpqcrystals_kyber512_avx2
 #define KYBER_K 2
 #include "kyber/params.h"
 #include "kyber/api_kyber.h"
 #include "kyber/api_kyber_zkpop.h"
 #include <stdint.h>
+#include "kyber_backend.h"
 #include <stdlib.h>
-int pqcrystals_kyber512_avx2_crypto_kem_keypair_nizkpop(uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size);
-int pqcrystals_kyber512_avx2_crypto_nizkpop_verify(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size);
+ZKPOP_DECLARE_KYBER_NIZKPOP(512)
+enum {
+	kyber512_zkpop_n = ZKPOP_N,
+	kyber512_zkpop_tau = ZKPOP_TAU,
+	kyber512_zkpop_symbytes = ZKPOP_SYMBYTES,
+	kyber512_zkpop_m_packedbytes = ZKPOP_M_PACKEDBYTES,
+	kyber512_zkpop_m_sigma_packedbytes = ZKPOP_M_SIGMA_PACKEDBYTES,
+};
 */
 import "C"
 import (
@@ -18,11 +24,14 @@ import (
 )
 
 func KeyPairKyber512NIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
 	pk := make([]byte, C.pqcrystals_kyber512_PUBLICKEYBYTES)
 	sk := make([]byte, C.pqcrystals_kyber512_SECRETKEYBYTES)
 	var zkpop_c *C.uint8_t
 	var zkpop_size_c C.size_t
-	ret := C.pqcrystals_kyber512_avx2_crypto_kem_keypair_nizkpop(
+	ret := C.kyber512_keypair_nizkpop(
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
 		&zkpop_c,
@@ -36,11 +45,32 @@ func KeyPairKyber512NIZKPoP() ([]byte, []byte, []byte, error) {
 	return pk, sk, zkpopGo, nil
 }
 
+var kyber512ProofShape = kyberProofShape{
+	pkSize:       C.pqcrystals_kyber512_PUBLICKEYBYTES,
+	symBytes:     C.kyber512_zkpop_symbytes,
+	mPacked:      C.kyber512_zkpop_m_packedbytes,
+	mSigmaPacked: C.kyber512_zkpop_m_sigma_packedbytes,
+}
+
+// kyber512DefaultProfile is the profile set in kyber/params.h.
+var kyber512DefaultProfile = Profile{ID: 0, N: C.kyber512_zkpop_n, Tau: C.kyber512_zkpop_tau}
+
+// VerifyKyber512NIZKPoP checks a Kyber512 NIZKPoP and returns a *VerifyError
+// explaining why it was rejected, or nil if it is valid.
+func VerifyKyber512NIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof("Kyber512-NIZKPoP", kyber512ProofShape.limits(kyber512DefaultProfile), pk, zkpop, func() int {
+		return int(C.kyber512_nizkpop_verify(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop)),
+		))
+	})
+}
+
+// VerifyKyber512ZKPop reports whether zkpop is a valid NIZKPoP for pk.
 func VerifyKyber512ZKPop(pk []byte, zkpop []byte) bool {
-	ret := C.pqcrystals_kyber512_avx2_crypto_nizkpop_verify(
-		(*C.uchar)(unsafe.Pointer(&pk[0])),
-		(*C.uchar)(unsafe.Pointer(&zkpop[0])),
-		C.ulong(len(zkpop)),
-	)
-	return ret == 0
+	return VerifyKyber512NIZKPoP(pk, zkpop) == nil
 }
+
+// Kyber512NIZKPoP is Kyber512 with key generation producing a NIZKPoP.
+var Kyber512NIZKPoP ProvingKEM = kyber512KEM.withProof("Kyber512-NIZKPoP", kyber512DefaultProfile, kyber512ProofShape.limits(kyber512DefaultProfile), KeyPairKyber512NIZKPoP, VerifyKyber512NIZKPoP)
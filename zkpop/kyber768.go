@@ -1,15 +1,21 @@
 package zkpop
 
 /*
-#cgo LDFLAGS: -lpqcrystals_kyber768_avx2
 #define KYBER_K 3
 #include "kyber/params.h"
 #include "kyber/api_kyber.h"
 #include "kyber/api_kyber_zkpop.h"
 #include <stdint.h>
+#include "kyber_backend.h"
 #include <stdlib.h>
-int pqcrystals_kyber768_avx2_crypto_kem_keypair_nizkpop(uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size);
-int pqcrystals_kyber768_avx2_crypto_nizkpop_verify(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size);
+ZKPOP_DECLARE_KYBER_NIZKPOP(768)
+enum {
+	kyber768_zkpop_n = ZKPOP_N,
+	kyber768_zkpop_tau = ZKPOP_TAU,
+	kyber768_zkpop_symbytes = ZKPOP_SYMBYTES,
+	kyber768_zkpop_m_packedbytes = ZKPOP_M_PACKEDBYTES,
+	kyber768_zkpop_m_sigma_packedbytes = ZKPOP_M_SIGMA_PACKEDBYTES,
+};
 */
 import "C"
 import (
@@ -18,11 +24,14 @@ import (
 )
 
 func KeyPairKyber768NIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
 	pk := make([]byte, C.pqcrystals_kyber768_PUBLICKEYBYTES)
 	sk := make([]byte, C.pqcrystals_kyber768_SECRETKEYBYTES)
 	var zkpop_c *C.uint8_t
 	var zkpop_size_c C.size_t
-	ret := C.pqcrystals_kyber768_avx2_crypto_kem_keypair_nizkpop(
+	ret := C.kyber768_keypair_nizkpop(
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
 		&zkpop_c,
@@ -36,11 +45,32 @@ func KeyPairKyber768NIZKPoP() ([]byte, []byte, []byte, error) {
 	return pk, sk, zkpopGo, nil
 }
 
+var kyber768ProofShape = kyberProofShape{
+	pkSize:       C.pqcrystals_kyber768_PUBLICKEYBYTES,
+	symBytes:     C.kyber768_zkpop_symbytes,
+	mPacked:      C.kyber768_zkpop_m_packedbytes,
+	mSigmaPacked: C.kyber768_zkpop_m_sigma_packedbytes,
+}
+
+// kyber768DefaultProfile is the profile set in kyber/params.h.
+var kyber768DefaultProfile = Profile{ID: 0, N: C.kyber768_zkpop_n, Tau: C.kyber768_zkpop_tau}
+
+// VerifyKyber768NIZKPoP checks a Kyber768 NIZKPoP and returns a *VerifyError
+// explaining why it was rejected, or nil if it is valid.
+func VerifyKyber768NIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof("Kyber768-NIZKPoP", kyber768ProofShape.limits(kyber768DefaultProfile), pk, zkpop, func() int {
+		return int(C.kyber768_nizkpop_verify(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop)),
+		))
+	})
+}
+
+// VerifyKyber768ZKPop reports whether zkpop is a valid NIZKPoP for pk.
 func VerifyKyber768ZKPop(pk []byte, zkpop []byte) bool {
-	ret := C.pqcrystals_kyber768_avx2_crypto_nizkpop_verify(
-		(*C.uchar)(unsafe.Pointer(&pk[0])),
-		(*C.uchar)(unsafe.Pointer(&zkpop[0])),
-		C.ulong(len(zkpop)),
-	)
-	return ret == 0
+	return VerifyKyber768NIZKPoP(pk, zkpop) == nil
 }
+
+// Kyber768NIZKPoP is Kyber768 with key generation producing a NIZKPoP.
+var Kyber768NIZKPoP ProvingKEM = kyber768KEM.withProof("Kyber768-NIZKPoP", kyber768DefaultProfile, kyber768ProofShape.limits(kyber768DefaultProfile), KeyPairKyber768NIZKPoP, VerifyKyber768NIZKPoP)
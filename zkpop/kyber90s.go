@@ -0,0 +1,259 @@
+// Kyber 90s bindings. The 90s variant replaces SHAKE and SHA-3 with
+// AES-256-CTR and SHA-2, which is faster on CPUs with AES-NI. Keys and
+// ciphertexts have the same sizes as plain Kyber but are not interchangeable.
+//
+// Only the KEM is bound: the kyber-zkpop Makefile builds the 90s libraries
+// without zkpop.c, so they export no NIZKPoP functions.
+package zkpop
+
+/*
+#include "kyber/api_kyber.h"
+#include "kyber_backend.h"
+#include <stdint.h>
+ZKPOP_DECLARE_KYBER(512_90s)
+ZKPOP_DECLARE_KYBER(768_90s)
+ZKPOP_DECLARE_KYBER(1024_90s)
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// KeyPairKyber512_90s generates a Kyber512-90s key pair.
+func KeyPairKyber512_90s() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	pk := make([]byte, C.pqcrystals_kyber512_90s_avx2_PUBLICKEYBYTES)
+	sk := make([]byte, C.pqcrystals_kyber512_90s_avx2_SECRETKEYBYTES)
+
+	ret := C.kyber512_90s_keypair(
+		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
+		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to generate Kyber512-90s keypair: %d", ret)
+	}
+	return pk, sk, nil
+}
+
+// EncapsKyber512_90s returns a ciphertext and shared secret for a Kyber512-90s
+// public key.
+func EncapsKyber512_90s(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "Kyber512-90s", pk, C.pqcrystals_kyber512_90s_avx2_PUBLICKEYBYTES); err != nil {
+		return nil, nil, err
+	}
+	ss = make([]byte, C.pqcrystals_kyber512_90s_avx2_BYTES)
+	ct = make([]byte, C.pqcrystals_kyber512_90s_avx2_CIPHERTEXTBYTES)
+
+	ret := C.kyber512_90s_enc(
+		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
+		(*C.uint8_t)(unsafe.Pointer(&ss[0])),
+		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to encaps Kyber512-90s: %d", ret)
+	}
+	return ct, ss, nil
+}
+
+// DecapsKyber512_90s recovers the shared secret from a Kyber512-90s ciphertext.
+func DecapsKyber512_90s(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "Kyber512-90s", ct, C.pqcrystals_kyber512_90s_avx2_CIPHERTEXTBYTES); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "Kyber512-90s", sk, C.pqcrystals_kyber512_90s_avx2_SECRETKEYBYTES); err != nil {
+		return nil, err
+	}
+	css := make([]byte, C.pqcrystals_kyber512_90s_avx2_BYTES)
+
+	ret := C.kyber512_90s_dec(
+		(*C.uint8_t)(unsafe.Pointer(&css[0])),
+		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
+		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("failed to decapsulate Kyber512-90s: %d", ret)
+	}
+	return css, nil
+}
+
+// KeyPairKyber768_90s generates a Kyber768-90s key pair.
+func KeyPairKyber768_90s() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	pk := make([]byte, C.pqcrystals_kyber768_90s_avx2_PUBLICKEYBYTES)
+	sk := make([]byte, C.pqcrystals_kyber768_90s_avx2_SECRETKEYBYTES)
+
+	ret := C.kyber768_90s_keypair(
+		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
+		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to generate Kyber768-90s keypair: %d", ret)
+	}
+	return pk, sk, nil
+}
+
+// EncapsKyber768_90s returns a ciphertext and shared secret for a Kyber768-90s
+// public key.
+func EncapsKyber768_90s(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "Kyber768-90s", pk, C.pqcrystals_kyber768_90s_avx2_PUBLICKEYBYTES); err != nil {
+		return nil, nil, err
+	}
+	ss = make([]byte, C.pqcrystals_kyber768_90s_avx2_BYTES)
+	ct = make([]byte, C.pqcrystals_kyber768_90s_avx2_CIPHERTEXTBYTES)
+
+	ret := C.kyber768_90s_enc(
+		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
+		(*C.uint8_t)(unsafe.Pointer(&ss[0])),
+		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to encaps Kyber768-90s: %d", ret)
+	}
+	return ct, ss, nil
+}
+
+// DecapsKyber768_90s recovers the shared secret from a Kyber768-90s ciphertext.
+func DecapsKyber768_90s(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "Kyber768-90s", ct, C.pqcrystals_kyber768_90s_avx2_CIPHERTEXTBYTES); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "Kyber768-90s", sk, C.pqcrystals_kyber768_90s_avx2_SECRETKEYBYTES); err != nil {
+		return nil, err
+	}
+	css := make([]byte, C.pqcrystals_kyber768_90s_avx2_BYTES)
+
+	ret := C.kyber768_90s_dec(
+		(*C.uint8_t)(unsafe.Pointer(&css[0])),
+		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
+		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("failed to decapsulate Kyber768-90s: %d", ret)
+	}
+	return css, nil
+}
+
+// KeyPairKyber1024_90s generates a Kyber1024-90s key pair.
+func KeyPairKyber1024_90s() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	pk := make([]byte, C.pqcrystals_kyber1024_90s_avx2_PUBLICKEYBYTES)
+	sk := make([]byte, C.pqcrystals_kyber1024_90s_avx2_SECRETKEYBYTES)
+
+	ret := C.kyber1024_90s_keypair(
+		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
+		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to generate Kyber1024-90s keypair: %d", ret)
+	}
+	return pk, sk, nil
+}
+
+// EncapsKyber1024_90s returns a ciphertext and shared secret for a Kyber1024-90s
+// public key.
+func EncapsKyber1024_90s(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "Kyber1024-90s", pk, C.pqcrystals_kyber1024_90s_avx2_PUBLICKEYBYTES); err != nil {
+		return nil, nil, err
+	}
+	ss = make([]byte, C.pqcrystals_kyber1024_90s_avx2_BYTES)
+	ct = make([]byte, C.pqcrystals_kyber1024_90s_avx2_CIPHERTEXTBYTES)
+
+	ret := C.kyber1024_90s_enc(
+		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
+		(*C.uint8_t)(unsafe.Pointer(&ss[0])),
+		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
+	)
+	if ret != 0 {
+		return nil, nil, fmt.Errorf("failed to encaps Kyber1024-90s: %d", ret)
+	}
+	return ct, ss, nil
+}
+
+// DecapsKyber1024_90s recovers the shared secret from a Kyber1024-90s ciphertext.
+func DecapsKyber1024_90s(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "Kyber1024-90s", ct, C.pqcrystals_kyber1024_90s_avx2_CIPHERTEXTBYTES); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "Kyber1024-90s", sk, C.pqcrystals_kyber1024_90s_avx2_SECRETKEYBYTES); err != nil {
+		return nil, err
+	}
+	css := make([]byte, C.pqcrystals_kyber1024_90s_avx2_BYTES)
+
+	ret := C.kyber1024_90s_dec(
+		(*C.uint8_t)(unsafe.Pointer(&css[0])),
+		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
+		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("failed to decapsulate Kyber1024-90s: %d", ret)
+	}
+	return css, nil
+}
+
+// Kyber512_90s is the 90s variant of Kyber512 (NIST security level 1).
+var Kyber512_90s KEM = &kemScheme{
+	name:     "Kyber512-90s",
+	pkSize:   C.pqcrystals_kyber512_90s_avx2_PUBLICKEYBYTES,
+	skSize:   C.pqcrystals_kyber512_90s_avx2_SECRETKEYBYTES,
+	ctSize:   C.pqcrystals_kyber512_90s_avx2_CIPHERTEXTBYTES,
+	ssSize:   C.pqcrystals_kyber512_90s_avx2_BYTES,
+	keyPair:  KeyPairKyber512_90s,
+	seedSize: SeedSize,
+	fromSeed: KeyPairKyber512_90sFromSeed,
+	encaps:   EncapsKyber512_90s,
+	decaps:   DecapsKyber512_90s,
+}
+
+// Kyber768_90s is the 90s variant of Kyber768 (NIST security level 3).
+var Kyber768_90s KEM = &kemScheme{
+	name:     "Kyber768-90s",
+	pkSize:   C.pqcrystals_kyber768_90s_avx2_PUBLICKEYBYTES,
+	skSize:   C.pqcrystals_kyber768_90s_avx2_SECRETKEYBYTES,
+	ctSize:   C.pqcrystals_kyber768_90s_avx2_CIPHERTEXTBYTES,
+	ssSize:   C.pqcrystals_kyber768_90s_avx2_BYTES,
+	keyPair:  KeyPairKyber768_90s,
+	seedSize: SeedSize,
+	fromSeed: KeyPairKyber768_90sFromSeed,
+	encaps:   EncapsKyber768_90s,
+	decaps:   DecapsKyber768_90s,
+}
+
+// Kyber1024_90s is the 90s variant of Kyber1024 (NIST security level 5).
+var Kyber1024_90s KEM = &kemScheme{
+	name:     "Kyber1024-90s",
+	pkSize:   C.pqcrystals_kyber1024_90s_avx2_PUBLICKEYBYTES,
+	skSize:   C.pqcrystals_kyber1024_90s_avx2_SECRETKEYBYTES,
+	ctSize:   C.pqcrystals_kyber1024_90s_avx2_CIPHERTEXTBYTES,
+	ssSize:   C.pqcrystals_kyber1024_90s_avx2_BYTES,
+	keyPair:  KeyPairKyber1024_90s,
+	seedSize: SeedSize,
+	fromSeed: KeyPairKyber1024_90sFromSeed,
+	encaps:   EncapsKyber1024_90s,
+	decaps:   DecapsKyber1024_90s,
+}
@@ -3,10 +3,14 @@ package zkpop
 
 /*
 #include "kyber/api_kyber.h" // Contém as definições para Kyber512, 768, 1024 KEM
+#include "kyber_backend.h"
 #include <stdint.h>
 #include <stdlib.h>
 #include <openssl/evp.h> //from OpenSSL 1.1.1
 #include <openssl/aes.h>
+ZKPOP_DECLARE_KYBER(512)
+ZKPOP_DECLARE_KYBER(768)
+ZKPOP_DECLARE_KYBER(1024)
 */
 import "C"
 
@@ -15,12 +19,15 @@ import (
 	"unsafe"
 )
 
-//binding for pqcrystals_kyber512_avx2_keypair (Mantenha o código existente)
+//binding for pqcrystals_kyber512_{avx2,ref}_keypair (Mantenha o código existente)
 func KeyPairKyber512() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
 	pk := make([]byte, C.pqcrystals_kyber512_PUBLICKEYBYTES)
 	sk := make([]byte, C.pqcrystals_kyber512_SECRETKEYBYTES)
 
-	ret := C.pqcrystals_kyber512_avx2_keypair((*C.uint8_t)(unsafe.Pointer(&pk[0])),
+	ret := C.kyber512_keypair((*C.uint8_t)(unsafe.Pointer(&pk[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])))
 
 	if ret != 0 {
@@ -31,10 +38,16 @@ func KeyPairKyber512() ([]byte, []byte, error) {
 
 //Encapsulation for a given public key pk (Mantenha o código existente)
 func EncapsKyber512(pk []byte) ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "Kyber512", pk, C.pqcrystals_kyber512_PUBLICKEYBYTES); err != nil {
+		return nil, nil, err
+	}
 	ss := make([]byte, C.pqcrystals_kyber512_BYTES)
 	ct := make([]byte, C.pqcrystals_kyber512_CIPHERTEXTBYTES)
 
-	ret := C.pqcrystals_kyber512_avx2_enc((*C.uint8_t)(unsafe.Pointer(&ct[0])),
+	ret := C.kyber512_enc((*C.uint8_t)(unsafe.Pointer(&ct[0])),
 		(*C.uint8_t)(unsafe.Pointer(&ss[0])),
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])))
 	if ret != 0 {
@@ -45,9 +58,18 @@ func EncapsKyber512(pk []byte) ([]byte, []byte, error) {
 
 //Given a ciphertext ct and a private key sk (Mantenha o código existente)
 func DecapsKyber512(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "Kyber512", ct, C.pqcrystals_kyber512_CIPHERTEXTBYTES); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "Kyber512", sk, C.pqcrystals_kyber512_SECRETKEYBYTES); err != nil {
+		return nil, err
+	}
 	css := make([]byte, C.pqcrystals_kyber512_BYTES)
 
-	ret := C.pqcrystals_kyber512_avx2_dec((*C.uint8_t)(unsafe.Pointer(&css[0])),
+	ret := C.kyber512_dec((*C.uint8_t)(unsafe.Pointer(&css[0])),
 		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])))
 	if ret != 0 {
@@ -60,10 +82,13 @@ func DecapsKyber512(ct []byte, sk []byte) ([]byte, error) {
 
 // KeyPairKyber768 gera um par de chaves Kyber768.
 func KeyPairKyber768() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
 	pk := make([]byte, C.pqcrystals_kyber768_PUBLICKEYBYTES)
 	sk := make([]byte, C.pqcrystals_kyber768_SECRETKEYBYTES)
 
-	ret := C.pqcrystals_kyber768_avx2_keypair( // Nome da função C de api.h
+	ret := C.kyber768_keypair(
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
 	)
@@ -76,10 +101,16 @@ func KeyPairKyber768() ([]byte, []byte, error) {
 
 // EncapsKyber768 encapsula uma chave de sessão usando a chave pública Kyber768.
 func EncapsKyber768(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "Kyber768", pk, C.pqcrystals_kyber768_PUBLICKEYBYTES); err != nil {
+		return nil, nil, err
+	}
 	ss = make([]byte, C.pqcrystals_kyber768_BYTES)           // Tamanho do shared secret
 	ct = make([]byte, C.pqcrystals_kyber768_CIPHERTEXTBYTES) // Tamanho do ciphertext
 
-	ret := C.pqcrystals_kyber768_avx2_enc( // Nome da função C de api.h
+	ret := C.kyber768_enc(
 		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
 		(*C.uint8_t)(unsafe.Pointer(&ss[0])),
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
@@ -92,9 +123,18 @@ func EncapsKyber768(pk []byte) (ct, ss []byte, err error) {
 
 // DecapsKyber768 decapsula uma chave de sessão usando o texto cifrado e a chave privada Kyber768.
 func DecapsKyber768(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "Kyber768", ct, C.pqcrystals_kyber768_CIPHERTEXTBYTES); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "Kyber768", sk, C.pqcrystals_kyber768_SECRETKEYBYTES); err != nil {
+		return nil, err
+	}
 	css := make([]byte, C.pqcrystals_kyber768_BYTES) // Tamanho do shared secret
 
-	ret := C.pqcrystals_kyber768_avx2_dec( // Nome da função C de api.h
+	ret := C.kyber768_dec(
 		(*C.uint8_t)(unsafe.Pointer(&css[0])),
 		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
@@ -109,10 +149,13 @@ func DecapsKyber768(ct []byte, sk []byte) ([]byte, error) {
 
 // KeyPairKyber1024 gera um par de chaves Kyber1024.
 func KeyPairKyber1024() ([]byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
 	pk := make([]byte, C.pqcrystals_kyber1024_PUBLICKEYBYTES)
 	sk := make([]byte, C.pqcrystals_kyber1024_SECRETKEYBYTES)
 
-	ret := C.pqcrystals_kyber1024_avx2_keypair( // Nome da função C de api.h
+	ret := C.kyber1024_keypair(
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
 	)
@@ -125,10 +168,16 @@ func KeyPairKyber1024() ([]byte, []byte, error) {
 
 // EncapsKyber1024 encapsula uma chave de sessão usando a chave pública Kyber1024.
 func EncapsKyber1024(pk []byte) (ct, ss []byte, err error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, "Kyber1024", pk, C.pqcrystals_kyber1024_PUBLICKEYBYTES); err != nil {
+		return nil, nil, err
+	}
 	ss = make([]byte, C.pqcrystals_kyber1024_BYTES)           // Tamanho do shared secret
 	ct = make([]byte, C.pqcrystals_kyber1024_CIPHERTEXTBYTES) // Tamanho do ciphertext
 
-	ret := C.pqcrystals_kyber1024_avx2_enc( // Nome da função C de api.h
+	ret := C.kyber1024_enc(
 		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
 		(*C.uint8_t)(unsafe.Pointer(&ss[0])),
 		(*C.uint8_t)(unsafe.Pointer(&pk[0])),
@@ -141,9 +190,18 @@ func EncapsKyber1024(pk []byte) (ct, ss []byte, err error) {
 
 // DecapsKyber1024 decapsula uma chave de sessão usando o texto cifrado e a chave privada Kyber1024.
 func DecapsKyber1024(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidCiphertextSize, "Kyber1024", ct, C.pqcrystals_kyber1024_CIPHERTEXTBYTES); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "Kyber1024", sk, C.pqcrystals_kyber1024_SECRETKEYBYTES); err != nil {
+		return nil, err
+	}
 	css := make([]byte, C.pqcrystals_kyber1024_BYTES) // Tamanho do shared secret
 
-	ret := C.pqcrystals_kyber1024_avx2_dec( // Nome da função C de api.h
+	ret := C.kyber1024_dec(
 		(*C.uint8_t)(unsafe.Pointer(&css[0])),
 		(*C.uint8_t)(unsafe.Pointer(&ct[0])),
 		(*C.uint8_t)(unsafe.Pointer(&sk[0])),
@@ -152,4 +210,52 @@ func DecapsKyber1024(ct []byte, sk []byte) ([]byte, error) {
 		return nil, fmt.Errorf("failed to decapsulate Kyber1024: %d", ret)
 	}
 	return css, nil
-}
\ No newline at end of file
+}
+
+// Kyber512 is the round-3 CRYSTALS-Kyber KEM at NIST security level 1.
+var Kyber512 KEM = kyber512KEM
+
+var kyber512KEM = &kemScheme{
+	name:     "Kyber512",
+	pkSize:   C.pqcrystals_kyber512_PUBLICKEYBYTES,
+	skSize:   C.pqcrystals_kyber512_SECRETKEYBYTES,
+	ctSize:   C.pqcrystals_kyber512_CIPHERTEXTBYTES,
+	ssSize:   C.pqcrystals_kyber512_BYTES,
+	keyPair:  KeyPairKyber512,
+	seedSize: SeedSize,
+	fromSeed: KeyPairKyber512FromSeed,
+	encaps:   EncapsKyber512,
+	decaps:   DecapsKyber512,
+}
+
+// Kyber768 is the round-3 CRYSTALS-Kyber KEM at NIST security level 3.
+var Kyber768 KEM = kyber768KEM
+
+var kyber768KEM = &kemScheme{
+	name:     "Kyber768",
+	pkSize:   C.pqcrystals_kyber768_PUBLICKEYBYTES,
+	skSize:   C.pqcrystals_kyber768_SECRETKEYBYTES,
+	ctSize:   C.pqcrystals_kyber768_CIPHERTEXTBYTES,
+	ssSize:   C.pqcrystals_kyber768_BYTES,
+	keyPair:  KeyPairKyber768,
+	seedSize: SeedSize,
+	fromSeed: KeyPairKyber768FromSeed,
+	encaps:   EncapsKyber768,
+	decaps:   DecapsKyber768,
+}
+
+// Kyber1024 is the round-3 CRYSTALS-Kyber KEM at NIST security level 5.
+var Kyber1024 KEM = kyber1024KEM
+
+var kyber1024KEM = &kemScheme{
+	name:     "Kyber1024",
+	pkSize:   C.pqcrystals_kyber1024_PUBLICKEYBYTES,
+	skSize:   C.pqcrystals_kyber1024_SECRETKEYBYTES,
+	ctSize:   C.pqcrystals_kyber1024_CIPHERTEXTBYTES,
+	ssSize:   C.pqcrystals_kyber1024_BYTES,
+	keyPair:  KeyPairKyber1024,
+	seedSize: SeedSize,
+	fromSeed: KeyPairKyber1024FromSeed,
+	encaps:   EncapsKyber1024,
+	decaps:   DecapsKyber1024,
+}
@@ -0,0 +1,55 @@
+/********************************************************************************************
+* Backend selection for the Kyber bindings.
+*
+* The avx2 and ref builds of kyber-zkpop export the same API under different
+* namespaces (pqcrystals_kyber512_avx2_* and pqcrystals_kyber512_ref_*). The
+* Go files call the kyber<level>_* wrappers below; ZKPOP_REF, set by the
+* zkpop_ref build tag, picks which library they forward to.
+*********************************************************************************************/
+
+#ifndef ZKPOP_KYBER_BACKEND_H
+#define ZKPOP_KYBER_BACKEND_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+#ifdef ZKPOP_REF
+#define ZKPOP_KYBER(level, s) pqcrystals_kyber##level##_ref_##s
+#else
+#define ZKPOP_KYBER(level, s) pqcrystals_kyber##level##_avx2_##s
+#endif
+
+#define ZKPOP_DECLARE_KYBER(level) \
+  int ZKPOP_KYBER(level, keypair)(uint8_t *pk, uint8_t *sk); \
+  int ZKPOP_KYBER(level, enc)(uint8_t *ct, uint8_t *ss, const uint8_t *pk); \
+  int ZKPOP_KYBER(level, dec)(uint8_t *ss, const uint8_t *ct, const uint8_t *sk); \
+  static inline int kyber##level##_keypair(uint8_t *pk, uint8_t *sk) { \
+    return ZKPOP_KYBER(level, keypair)(pk, sk); \
+  } \
+  static inline int kyber##level##_enc(uint8_t *ct, uint8_t *ss, const uint8_t *pk) { \
+    return ZKPOP_KYBER(level, enc)(ct, ss, pk); \
+  } \
+  static inline int kyber##level##_dec(uint8_t *ss, const uint8_t *ct, const uint8_t *sk) { \
+    return ZKPOP_KYBER(level, dec)(ss, ct, sk); \
+  }
+
+#define ZKPOP_DECLARE_KYBER_NIZKPOP(level) \
+  int ZKPOP_KYBER(level, crypto_kem_keypair_nizkpop)(uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size); \
+  int ZKPOP_KYBER(level, crypto_nizkpop_verify)(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size); \
+  static inline int kyber##level##_keypair_nizkpop(uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size) { \
+    return ZKPOP_KYBER(level, crypto_kem_keypair_nizkpop)(pk, sk, zkpop, zkpop_size); \
+  } \
+  static inline int kyber##level##_nizkpop_verify(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size) { \
+    return ZKPOP_KYBER(level, crypto_nizkpop_verify)(pk, zkpop, zkpop_size); \
+  }
+
+/*
+* Extra (ZKPOP_N, ZKPOP_TAU) profiles built by scripts/build-kyber-profiles.sh.
+* Their API is renamed to pqcrystals_kyber<level>_<backend>_n<N>t<TAU>_*, so
+* they link next to the default build.
+*/
+#define ZKPOP_DECLARE_KYBER_PROFILE(level, id, N, TAU) \
+  int ZKPOP_KYBER(level, n##N##t##TAU##_crypto_kem_keypair_nizkpop)(uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size); \
+  int ZKPOP_KYBER(level, n##N##t##TAU##_crypto_nizkpop_verify)(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size);
+
+#endif
@@ -0,0 +1,150 @@
+// ML-KEM (FIPS 203) schemes, implemented by Go's crypto/mlkem.
+//
+// ML-KEM is the standardised form of Kyber. Public keys share the round-3
+// encoding, but key derivation and decapsulation differ, so ML-KEM and
+// round-3 Kyber do not interoperate. Private keys are the 64-byte seed form
+// (d || z) defined by FIPS 203.
+//
+// There is no NIZKPoP for ML-KEM: the kyber-zkpop prover generates its own
+// round-3 key pair and cannot prove possession of an existing ML-KEM key.
+// ML-KEM-512 is not offered because crypto/mlkem does not implement it.
+package zkpop
+
+import (
+	"crypto/mlkem"
+	"fmt"
+)
+
+// KeyPairMLKEM768 generates an ML-KEM-768 key pair. The private key is the
+// 64-byte seed.
+func KeyPairMLKEM768() ([]byte, []byte, error) {
+	dk, err := mlkem.GenerateKey768()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate ML-KEM-768 keypair: %w", err)
+	}
+	return dk.EncapsulationKey().Bytes(), dk.Bytes(), nil
+}
+
+// KeyPairMLKEM768FromSeed derives an ML-KEM-768 key pair from the 64-byte
+// FIPS 203 seed d || z, which is also the returned private key.
+func KeyPairMLKEM768FromSeed(seed []byte) ([]byte, []byte, error) {
+	if err := checkSize(ErrInvalidSeedSize, "ML-KEM-768", seed, mlkem.SeedSize); err != nil {
+		return nil, nil, err
+	}
+	dk, err := mlkem.NewDecapsulationKey768(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dk.EncapsulationKey().Bytes(), dk.Bytes(), nil
+}
+
+// EncapsMLKEM768 returns a ciphertext and shared secret for an ML-KEM-768
+// encapsulation key.
+func EncapsMLKEM768(pk []byte) (ct, ss []byte, err error) {
+	if err := checkSize(ErrInvalidPublicKeySize, "ML-KEM-768", pk, mlkem.EncapsulationKeySize768); err != nil {
+		return nil, nil, err
+	}
+	ek, err := mlkem.NewEncapsulationKey768(pk)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidPublicKeySize, err)
+	}
+	ss, ct = ek.Encapsulate()
+	return ct, ss, nil
+}
+
+// DecapsMLKEM768 recovers the shared secret from an ML-KEM-768 ciphertext.
+func DecapsMLKEM768(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkSize(ErrInvalidCiphertextSize, "ML-KEM-768", ct, mlkem.CiphertextSize768); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "ML-KEM-768", sk, mlkem.SeedSize); err != nil {
+		return nil, err
+	}
+	dk, err := mlkem.NewDecapsulationKey768(sk)
+	if err != nil {
+		return nil, err
+	}
+	return dk.Decapsulate(ct)
+}
+
+// KeyPairMLKEM1024 generates an ML-KEM-1024 key pair. The private key is the
+// 64-byte seed.
+func KeyPairMLKEM1024() ([]byte, []byte, error) {
+	dk, err := mlkem.GenerateKey1024()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate ML-KEM-1024 keypair: %w", err)
+	}
+	return dk.EncapsulationKey().Bytes(), dk.Bytes(), nil
+}
+
+// KeyPairMLKEM1024FromSeed derives an ML-KEM-1024 key pair from the 64-byte
+// FIPS 203 seed d || z, which is also the returned private key.
+func KeyPairMLKEM1024FromSeed(seed []byte) ([]byte, []byte, error) {
+	if err := checkSize(ErrInvalidSeedSize, "ML-KEM-1024", seed, mlkem.SeedSize); err != nil {
+		return nil, nil, err
+	}
+	dk, err := mlkem.NewDecapsulationKey1024(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dk.EncapsulationKey().Bytes(), dk.Bytes(), nil
+}
+
+// EncapsMLKEM1024 returns a ciphertext and shared secret for an ML-KEM-1024
+// encapsulation key.
+func EncapsMLKEM1024(pk []byte) (ct, ss []byte, err error) {
+	if err := checkSize(ErrInvalidPublicKeySize, "ML-KEM-1024", pk, mlkem.EncapsulationKeySize1024); err != nil {
+		return nil, nil, err
+	}
+	ek, err := mlkem.NewEncapsulationKey1024(pk)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidPublicKeySize, err)
+	}
+	ss, ct = ek.Encapsulate()
+	return ct, ss, nil
+}
+
+// DecapsMLKEM1024 recovers the shared secret from an ML-KEM-1024 ciphertext.
+func DecapsMLKEM1024(ct []byte, sk []byte) ([]byte, error) {
+	if err := checkSize(ErrInvalidCiphertextSize, "ML-KEM-1024", ct, mlkem.CiphertextSize1024); err != nil {
+		return nil, err
+	}
+	if err := checkSize(ErrInvalidPrivateKeySize, "ML-KEM-1024", sk, mlkem.SeedSize); err != nil {
+		return nil, err
+	}
+	dk, err := mlkem.NewDecapsulationKey1024(sk)
+	if err != nil {
+		return nil, err
+	}
+	return dk.Decapsulate(ct)
+}
+
+// MLKEM768 is ML-KEM-768 as specified in FIPS 203.
+var MLKEM768 KEM = &kemScheme{
+	name:     "ML-KEM-768",
+	pkSize:   mlkem.EncapsulationKeySize768,
+	skSize:   mlkem.SeedSize,
+	ctSize:   mlkem.CiphertextSize768,
+	ssSize:   mlkem.SharedKeySize,
+	keyPair:  KeyPairMLKEM768,
+	seedSize: mlkem.SeedSize,
+	fromSeed: KeyPairMLKEM768FromSeed,
+	encaps:   EncapsMLKEM768,
+	decaps:   DecapsMLKEM768,
+	native:   true,
+}
+
+// MLKEM1024 is ML-KEM-1024 as specified in FIPS 203.
+var MLKEM1024 KEM = &kemScheme{
+	name:     "ML-KEM-1024",
+	pkSize:   mlkem.EncapsulationKeySize1024,
+	skSize:   mlkem.SeedSize,
+	ctSize:   mlkem.CiphertextSize1024,
+	ssSize:   mlkem.SharedKeySize,
+	keyPair:  KeyPairMLKEM1024,
+	seedSize: mlkem.SeedSize,
+	fromSeed: KeyPairMLKEM1024FromSeed,
+	encaps:   EncapsMLKEM1024,
+	decaps:   DecapsMLKEM1024,
+	native:   true,
+}
@@ -0,0 +1,272 @@
+package zkpop
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PEM files hold the DER encodings from der.go. Keys use block types named
+// after their scheme, such as "KYBER768 PUBLIC KEY", "FRODOKEM-640 PRIVATE
+// KEY" and "ML-KEM-768 ENCRYPTED PRIVATE KEY"; envelopes use "KEM NIZKPOP".
+//
+// Encrypted private keys are PKCS#8 EncryptedPrivateKeyInfo with PBES2,
+// PBKDF2-HMAC-SHA256 and AES-256-CBC (RFC 8018), as written by
+// "openssl pkcs8 -topk8 -v2 aes-256-cbc -v2prf hmacWithSHA256".
+
+const (
+	pemPublicKey           = " PUBLIC KEY"
+	pemPrivateKey          = " PRIVATE KEY"
+	pemEncryptedPrivateKey = " ENCRYPTED PRIVATE KEY"
+	pemNIZKPoP             = "KEM NIZKPOP"
+)
+
+var (
+	// ErrPassphraseRequired is returned by DecodePEM for an encrypted
+	// private key when no passphrase is given.
+	ErrPassphraseRequired = errors.New("passphrase required")
+	// ErrDecryptionFailed is returned by DecodePEM when an encrypted private
+	// key cannot be decrypted, usually because the passphrase is wrong.
+	ErrDecryptionFailed = errors.New("private key decryption failed")
+)
+
+// EncodePEM encodes a *PublicKey, *PrivateKey or *Envelope as a PEM block.
+// Private keys are written unencrypted; see EncodeEncryptedPEM.
+func EncodePEM(v any) ([]byte, error) {
+	var typ string
+	var der []byte
+	var err error
+	switch v := v.(type) {
+	case *PublicKey:
+		typ = pemName(v.scheme) + pemPublicKey
+		der, err = MarshalPKIXPublicKey(v)
+	case *PrivateKey:
+		typ = pemName(v.scheme) + pemPrivateKey
+		der, err = MarshalPKCS8PrivateKey(v)
+	case *Envelope:
+		typ = pemNIZKPoP
+		der, err = MarshalNIZKPoP(v)
+	default:
+		return nil, fmt.Errorf("cannot PEM encode %T", v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), nil
+}
+
+// EncodeEncryptedPEM encodes sk as a PEM block encrypted under passphrase.
+func EncodeEncryptedPEM(sk *PrivateKey, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrPassphraseRequired
+	}
+	der, err := MarshalPKCS8PrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := encryptPKCS8(der, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemName(sk.scheme) + pemEncryptedPrivateKey, Bytes: enc}), nil
+}
+
+// DecodePEM decodes the first PEM block in data and returns the
+// *PublicKey, *PrivateKey or *Envelope it holds, along with the remainder
+// of data. passphrase is only used for encrypted private keys. The scheme
+// named by the block type must match the one in the encoded key.
+func DecodePEM(data, passphrase []byte) (v any, rest []byte, err error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, data, errors.New("no PEM block found")
+	}
+	if len(block.Headers) != 0 {
+		return nil, rest, fmt.Errorf("unexpected headers in %s PEM block", block.Type)
+	}
+	switch typ := block.Type; {
+	case typ == pemNIZKPoP:
+		v, err = ParseNIZKPoP(block.Bytes)
+	case strings.HasSuffix(typ, pemPublicKey):
+		var pk *PublicKey
+		if pk, err = ParsePKIXPublicKey(block.Bytes); err == nil {
+			v, err = pk, checkPEMName(typ, pemPublicKey, pk.scheme)
+		}
+	case strings.HasSuffix(typ, pemEncryptedPrivateKey):
+		if len(passphrase) == 0 {
+			return nil, rest, ErrPassphraseRequired
+		}
+		var der []byte
+		var sk *PrivateKey
+		if der, err = decryptPKCS8(block.Bytes, passphrase); err == nil {
+			if sk, err = ParsePKCS8PrivateKey(der); err != nil {
+				err = fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+			} else {
+				v, err = sk, checkPEMName(typ, pemEncryptedPrivateKey, sk.scheme)
+			}
+		}
+	case strings.HasSuffix(typ, pemPrivateKey):
+		var sk *PrivateKey
+		if sk, err = ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			v, err = sk, checkPEMName(typ, pemPrivateKey, sk.scheme)
+		}
+	default:
+		err = fmt.Errorf("unsupported PEM block type %q", typ)
+	}
+	if err != nil {
+		return nil, rest, err
+	}
+	return v, rest, nil
+}
+
+// pemName is the block type prefix for keys of k.
+func pemName(k KEM) string {
+	return strings.ToUpper(keyScheme(k).Name())
+}
+
+func checkPEMName(typ, suffix string, k KEM) error {
+	if name := strings.TrimSuffix(typ, suffix); name != pemName(k) {
+		return fmt.Errorf("PEM block %q holds a %s key", typ, keyScheme(k).Name())
+	}
+	return nil
+}
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+const (
+	pbkdf2Iterations    = 600000
+	pbkdf2MaxIterations = 10000000
+	pbkdf2SaltSize      = 16
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pbes2Algorithm
+	EncryptedData []byte
+}
+
+type pbes2Algorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters pbes2Params
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pbkdf2Algorithm
+	EncryptionScheme  aesAlgorithm
+}
+
+type pbkdf2Algorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters pbkdf2Params
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier
+}
+
+type aesAlgorithm struct {
+	Algorithm asn1.ObjectIdentifier
+	IV        []byte
+}
+
+func encryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, pbkdf2SaltSize)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, string(passphrase), salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	pad := aes.BlockSize - len(der)%aes.BlockSize
+	ct := make([]byte, len(der)+pad)
+	copy(ct, der)
+	for i := len(der); i < len(ct); i++ {
+		ct[i] = byte(pad)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ct, ct)
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm: pbes2Algorithm{oidPBES2, pbes2Params{
+			KeyDerivationFunc: pbkdf2Algorithm{oidPBKDF2, pbkdf2Params{
+				Salt:           salt,
+				IterationCount: pbkdf2Iterations,
+				PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+			}},
+			EncryptionScheme: aesAlgorithm{oidAES256CBC, iv},
+		}},
+		EncryptedData: ct,
+	})
+}
+
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse EncryptedPrivateKeyInfo: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data after EncryptedPrivateKeyInfo")
+	}
+	params := info.Algorithm.Parameters
+	kdf := params.KeyDerivationFunc.Parameters
+	switch {
+	case !info.Algorithm.Algorithm.Equal(oidPBES2),
+		!params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2),
+		!kdf.PRF.Algorithm.Equal(oidHMACWithSHA256),
+		!params.EncryptionScheme.Algorithm.Equal(oidAES256CBC):
+		return nil, errors.New("unsupported private key encryption; want PBES2 with PBKDF2-HMAC-SHA256 and AES-256-CBC")
+	case kdf.KeyLength != 0 && kdf.KeyLength != 32:
+		return nil, fmt.Errorf("unsupported PBKDF2 key length %d", kdf.KeyLength)
+	case kdf.IterationCount < 1 || kdf.IterationCount > pbkdf2MaxIterations:
+		return nil, fmt.Errorf("PBKDF2 iteration count %d out of range", kdf.IterationCount)
+	case len(params.EncryptionScheme.IV) != aes.BlockSize:
+		return nil, errors.New("invalid AES-256-CBC IV")
+	case len(info.EncryptedData) == 0 || len(info.EncryptedData)%aes.BlockSize != 0:
+		return nil, ErrDecryptionFailed
+	}
+	key, err := pbkdf2.Key(sha256.New, string(passphrase), kdf.Salt, kdf.IterationCount, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	pt := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, params.EncryptionScheme.IV).CryptBlocks(pt, info.EncryptedData)
+
+	// A wrong passphrase almost always yields bad padding. Checking it in
+	// constant time keeps the failure from depending on the plaintext.
+	pad := int(pt[len(pt)-1])
+	good := subtle.ConstantTimeLessOrEq(1, pad) & subtle.ConstantTimeLessOrEq(pad, aes.BlockSize)
+	for i := 1; i <= aes.BlockSize; i++ {
+		inPad := subtle.ConstantTimeLessOrEq(i, pad)
+		good &= subtle.ConstantTimeByteEq(pt[len(pt)-i], byte(pad)) | (inPad ^ 1)
+	}
+	if good != 1 {
+		return nil, ErrDecryptionFailed
+	}
+	return pt[:len(pt)-pad], nil
+}
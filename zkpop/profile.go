@@ -0,0 +1,110 @@
+package zkpop
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Profile is a choice of MPC-in-the-head parameters for a NIZKPoP: the
+// number of parties ZKPOP_N and the number of repetitions ZKPOP_TAU. More
+// parties give smaller proofs at the cost of prover time.
+//
+// The default profile of each scheme has ID 0. Additional profiles are
+// available when the package is built with -tags zkpop_profiles and the
+// matching libraries from scripts/build-kyber-profiles.sh.
+type Profile struct {
+	ID  uint8
+	N   int
+	Tau int
+}
+
+func (p Profile) String() string {
+	return fmt.Sprintf("profile %d (N=%d, TAU=%d)", p.ID, p.N, p.Tau)
+}
+
+// ErrUnknownProfile is returned for a profile ID not compiled into this
+// build.
+var ErrUnknownProfile = errors.New("unknown proof profile")
+
+// frodoDefaultProfile is the profile frodo-zkpop is built with in the
+// README (ZKPOP_N=65536 ZKPOP_TAU=8).
+var frodoDefaultProfile = Profile{ID: 0, N: 65536, Tau: 8}
+
+type proofProfile struct {
+	Profile
+	limits  proofLimits
+	keyPair func() ([]byte, []byte, []byte, error)
+	verify  func(pk, proof []byte) error
+}
+
+func (s *provingScheme) Profiles() []Profile {
+	ps := make([]Profile, len(s.profiles))
+	for i, p := range s.profiles {
+		ps[i] = p.Profile
+	}
+	return ps
+}
+
+func (s *provingScheme) profile(id uint8) *proofProfile {
+	for _, p := range s.profiles {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *provingScheme) GenerateKeyPairWithProfile(id uint8) ([]byte, []byte, []byte, error) {
+	p := s.profile(id)
+	if p == nil {
+		return nil, nil, nil, fmt.Errorf("%w: %s has no profile %d", ErrUnknownProfile, s.name, id)
+	}
+	pk, sk, proof, err := s.keyPairWithProof(p)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pk, sk, append([]byte{id}, proof...), nil
+}
+
+func (s *provingScheme) VerifyProfiledProof(pk, proof []byte) error {
+	if len(proof) == 0 {
+		return &VerifyError{Scheme: s.name, Reason: ReasonTruncated, Err: ErrEmptyProof}
+	}
+	p := s.profile(proof[0])
+	if p == nil {
+		return &VerifyError{Scheme: s.name, Reason: ReasonWrongParameterSet,
+			Err: fmt.Errorf("%w %d", ErrUnknownProfile, proof[0])}
+	}
+	return p.verify(pk, proof[1:])
+}
+
+// addProfile registers an additional profile. It is called from init
+// functions of optional builds and panics on a duplicate ID.
+func (s *provingScheme) addProfile(p Profile, l proofLimits, keyPair func() ([]byte, []byte, []byte, error), verify func(pk, proof []byte) error) {
+	if s.profile(p.ID) != nil {
+		panic(fmt.Sprintf("zkpop: duplicate %s for %s", p, s.name))
+	}
+	s.profiles = append(s.profiles, &proofProfile{p, l, keyPair, verify})
+}
+
+// kyberProofShape holds the constants from kyber/params.h that, together
+// with a profile, determine the size of a Kyber proof.
+type kyberProofShape struct {
+	pkSize       int
+	symBytes     int // ZKPOP_SYMBYTES
+	mPacked      int // ZKPOP_M_PACKEDBYTES
+	mSigmaPacked int // ZKPOP_M_SIGMA_PACKEDBYTES
+}
+
+// limits bounds proofs for the profile. The minimum is the three
+// ZKPOP_SYMBYTES hashes that open every proof; the maximum mirrors
+// KYBER_ZKPOP_MAXBYTES.
+func (k kyberProofShape) limits(p Profile) proofLimits {
+	logN := bits.Len(uint(p.N)) - 1
+	return proofLimits{
+		pkSize: k.pkSize,
+		min:    3 * k.symBytes,
+		max:    k.symBytes*(3+p.Tau*(logN+2)) + k.mPacked*p.Tau + k.mSigmaPacked,
+	}
+}
@@ -0,0 +1,8 @@
+//go:build zkpop_profiles && !zkpop_ref
+
+package zkpop
+
+/*
+#cgo LDFLAGS: ${SRCDIR}/../build/kyber/avx2/libkyber_profiles.a
+*/
+import "C"
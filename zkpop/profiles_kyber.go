@@ -0,0 +1,111 @@
+//go:build zkpop_profiles
+
+// Additional NIZKPoP profiles for Kyber. Each profile is a separate build of
+// kyber-zkpop with its own ZKPOP_N and ZKPOP_TAU, produced (with renamed
+// symbols) by scripts/build-kyber-profiles.sh. Every profile keeps the
+// soundness N^-TAU of the default profile of its level; more parties mean a
+// shorter proof and a slower prover.
+
+package zkpop
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include "kyber_backend.h"
+
+// Keep in sync with PROFILES in scripts/build-kyber-profiles.sh.
+#define KYBER_PROFILES(X) \
+  X(512, 1, 4, 64) X(512, 2, 16, 32) X(512, 3, 256, 16) \
+  X(768, 1, 16, 48) X(768, 2, 256, 24) \
+  X(1024, 1, 16, 64) X(1024, 2, 256, 32)
+
+KYBER_PROFILES(ZKPOP_DECLARE_KYBER_PROFILE)
+
+struct kyber_profile {
+  int level, id, n, tau;
+  int (*keypair)(uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size);
+  int (*verify)(const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size);
+};
+
+#define KYBER_PROFILE_ENTRY(level, id, N, TAU) { level, id, N, TAU, \
+  ZKPOP_KYBER(level, n##N##t##TAU##_crypto_kem_keypair_nizkpop), \
+  ZKPOP_KYBER(level, n##N##t##TAU##_crypto_nizkpop_verify) },
+
+static const struct kyber_profile kyber_profiles[] = { KYBER_PROFILES(KYBER_PROFILE_ENTRY) };
+
+static int kyber_profiles_len(void) {
+  return sizeof(kyber_profiles) / sizeof(kyber_profiles[0]);
+}
+
+static struct kyber_profile kyber_profile_at(int i) {
+  return kyber_profiles[i];
+}
+
+static int kyber_profile_keypair(int i, uint8_t *pk, uint8_t *sk, uint8_t **zkpop, size_t *zkpop_size) {
+  return kyber_profiles[i].keypair(pk, sk, zkpop, zkpop_size);
+}
+
+static int kyber_profile_verify(int i, const unsigned char *pk, const unsigned char *zkpop, unsigned long zkpop_size) {
+  return kyber_profiles[i].verify(pk, zkpop, zkpop_size);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+func init() {
+	for i := C.int(0); i < C.kyber_profiles_len(); i++ {
+		cp := C.kyber_profile_at(i)
+		var s *provingScheme
+		var shape kyberProofShape
+		switch cp.level {
+		case 512:
+			s, shape = Kyber512NIZKPoP.(*provingScheme), kyber512ProofShape
+		case 768:
+			s, shape = Kyber768NIZKPoP.(*provingScheme), kyber768ProofShape
+		case 1024:
+			s, shape = Kyber1024NIZKPoP.(*provingScheme), kyber1024ProofShape
+		}
+		p := Profile{ID: uint8(cp.id), N: int(cp.n), Tau: int(cp.tau)}
+		l := shape.limits(p)
+		s.addProfile(p, l, kyberProfileKeyPair(i, s), kyberProfileVerify(i, s.name, l))
+	}
+}
+
+func kyberProfileKeyPair(i C.int, s *provingScheme) func() ([]byte, []byte, []byte, error) {
+	return func() ([]byte, []byte, []byte, error) {
+		if err := checkCPU(); err != nil {
+			return nil, nil, nil, err
+		}
+		pk := make([]byte, s.PublicKeySize())
+		sk := make([]byte, s.PrivateKeySize())
+		var zkpop_c *C.uint8_t
+		var zkpop_size_c C.size_t
+		ret := C.kyber_profile_keypair(i,
+			(*C.uint8_t)(unsafe.Pointer(&pk[0])),
+			(*C.uint8_t)(unsafe.Pointer(&sk[0])),
+			&zkpop_c,
+			&zkpop_size_c,
+		)
+		if ret != 0 {
+			return nil, nil, nil, fmt.Errorf("failed to generate %s keypair: %d", s.name, ret)
+		}
+		zkpopGo := C.GoBytes(unsafe.Pointer(zkpop_c), C.int(zkpop_size_c))
+		C.free(unsafe.Pointer(zkpop_c))
+		return pk, sk, zkpopGo, nil
+	}
+}
+
+func kyberProfileVerify(i C.int, name string, l proofLimits) func(pk, proof []byte) error {
+	return func(pk, proof []byte) error {
+		return verifyProof(name, l, pk, proof, func() int {
+			return int(C.kyber_profile_verify(i,
+				(*C.uchar)(unsafe.Pointer(&pk[0])),
+				(*C.uchar)(unsafe.Pointer(&proof[0])),
+				C.ulong(len(proof)),
+			))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build zkpop_profiles && zkpop_ref
+
+package zkpop
+
+/*
+#cgo LDFLAGS: ${SRCDIR}/../build/kyber/ref/libkyber_profiles.a
+*/
+import "C"
@@ -0,0 +1,183 @@
+package zkpop
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProvingKEM is a KEM whose key generation also produces a non-interactive
+// zero-knowledge proof of possession (NIZKPoP) of the private key.
+type ProvingKEM interface {
+	KEM
+
+	// GenerateKeyPairWithProof returns a fresh key pair together with a
+	// proof that the holder of pk knows the matching private key.
+	GenerateKeyPairWithProof() (pk, sk, proof []byte, err error)
+
+	// GenerateKeyPairWithProofFromSeed is like GenerateKeyPairWithProof but
+	// derives the keys and the proof from a seed of SeedSize bytes.
+	GenerateKeyPairWithProofFromSeed(seed []byte) (pk, sk, proof []byte, err error)
+
+	// VerifyProof checks proof against pk. It returns nil if the proof is
+	// accepted and a *VerifyError if it is rejected.
+	VerifyProof(pk, proof []byte) error
+
+	// Profiles lists the (ZKPOP_N, ZKPOP_TAU) parameter sets compiled into
+	// this build. The first is the default used by GenerateKeyPairWithProof.
+	Profiles() []Profile
+
+	// GenerateKeyPairWithProfile is like GenerateKeyPairWithProof but proves
+	// with the given profile and prefixes the proof with the profile ID.
+	GenerateKeyPairWithProfile(id uint8) (pk, sk, proof []byte, err error)
+
+	// VerifyProfiledProof checks a proof from GenerateKeyPairWithProfile,
+	// using its prefix to select the matching verifier.
+	VerifyProfiledProof(pk, proof []byte) error
+}
+
+// ErrProofRejected is reported when the C verifier rejects a proof.
+var ErrProofRejected = errors.New("proof rejected")
+
+// VerifyReason classifies why a proof was rejected.
+type VerifyReason int
+
+const (
+	// ReasonWrongParameterSet means the public key does not belong to the
+	// scheme the proof is checked against.
+	ReasonWrongParameterSet VerifyReason = iota + 1
+	// ReasonTruncated means the proof is empty or shorter than its fixed
+	// header.
+	ReasonTruncated
+	// ReasonTooLarge means the proof exceeds the maximum size the scheme can
+	// produce (KYBER_ZKPOP_MAXBYTES for Kyber).
+	ReasonTooLarge
+	// ReasonAuditFailed means the C verifier ran and rejected the proof.
+	ReasonAuditFailed
+	// ReasonKeyMismatch means an Envelope was made for a different public
+	// key.
+	ReasonKeyMismatch
+)
+
+func (r VerifyReason) String() string {
+	switch r {
+	case ReasonWrongParameterSet:
+		return "wrong parameter set"
+	case ReasonTruncated:
+		return "truncated proof"
+	case ReasonTooLarge:
+		return "proof too large"
+	case ReasonAuditFailed:
+		return "audit failed"
+	case ReasonKeyMismatch:
+		return "key mismatch"
+	}
+	return fmt.Sprintf("VerifyReason(%d)", int(r))
+}
+
+// VerifyError describes why a NIZKPoP was not accepted.
+type VerifyError struct {
+	Scheme string
+	Reason VerifyReason
+	// Ret is the value returned by the C verifier. It is only meaningful
+	// when Reason is ReasonAuditFailed; otherwise the C code was not called.
+	Ret int
+	Err error
+}
+
+func (e *VerifyError) Error() string {
+	if e.Reason == ReasonAuditFailed {
+		return fmt.Sprintf("%s: NIZKPoP verification failed: %v (ret %d)", e.Scheme, e.Err, e.Ret)
+	}
+	return fmt.Sprintf("%s: NIZKPoP verification failed: %v", e.Scheme, e.Err)
+}
+
+func (e *VerifyError) Unwrap() error { return e.Err }
+
+// provingScheme implements ProvingKEM on top of the per-scheme cgo bindings.
+type provingScheme struct {
+	*kemScheme
+	base     KEM
+	profiles []*proofProfile
+}
+
+func (s *provingScheme) GenerateKeyPairWithProof() ([]byte, []byte, []byte, error) {
+	return s.keyPairWithProof(s.profiles[0])
+}
+
+func (s *provingScheme) keyPairWithProof(p *proofProfile) ([]byte, []byte, []byte, error) {
+	var pk, sk, proof []byte
+	err := s.withRand(func() (err error) {
+		pk, sk, proof, err = p.keyPair()
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pk, sk, proof, nil
+}
+
+func (s *provingScheme) GenerateKeyPairWithProofFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed(s.name, seed, s.profiles[0].keyPair)
+}
+
+func (s *provingScheme) VerifyProof(pk, proof []byte) error {
+	return s.profiles[0].verify(pk, proof)
+}
+
+// proofLimits bounds the proofs a scheme can produce. A zero max means the
+// C headers do not define an upper bound.
+type proofLimits struct {
+	pkSize int
+	min    int
+	max    int
+}
+
+// verifyProof performs the checks shared by all schemes and then calls the
+// C verifier, which reports acceptance by returning zero.
+func verifyProof(scheme string, l proofLimits, pk, proof []byte, verify func() int) error {
+	if err := checkCPU(); err != nil {
+		return err
+	}
+	if err := checkSize(ErrInvalidPublicKeySize, scheme, pk, l.pkSize); err != nil {
+		return &VerifyError{Scheme: scheme, Reason: ReasonWrongParameterSet, Err: err}
+	}
+	if len(proof) == 0 {
+		return &VerifyError{Scheme: scheme, Reason: ReasonTruncated, Err: ErrEmptyProof}
+	}
+	if len(proof) < l.min {
+		return &VerifyError{Scheme: scheme, Reason: ReasonTruncated,
+			Err: fmt.Errorf("%w: %d bytes, want at least %d", ErrProofTruncated, len(proof), l.min)}
+	}
+	if l.max > 0 && len(proof) > l.max {
+		return &VerifyError{Scheme: scheme, Reason: ReasonTooLarge,
+			Err: fmt.Errorf("%w: %d bytes, limit %d", ErrProofTooLarge, len(proof), l.max)}
+	}
+	if ret := verify(); ret != 0 {
+		return &VerifyError{Scheme: scheme, Reason: ReasonAuditFailed, Ret: ret, Err: ErrProofRejected}
+	}
+	return nil
+}
+
+// withProof derives the NIZKPoP variant of a KEM scheme. Keys produced by
+// the two are interchangeable; only the name and the proof functions differ.
+// def is the profile the linked library was compiled with and l bounds its
+// proofs.
+func (s *kemScheme) withProof(name string, def Profile, l proofLimits, keyPairProof func() ([]byte, []byte, []byte, error), verify func(pk, proof []byte) error) *provingScheme {
+	k := *s
+	k.name = name
+	return &provingScheme{
+		kemScheme: &k,
+		base:      s,
+		profiles:  []*proofProfile{{def, l, keyPairProof, verify}},
+	}
+}
+
+// keyScheme returns the scheme that defines the key format of k, so that a
+// Kyber768-NIZKPoP key and a Kyber768 key compare as the same kind of key.
+func keyScheme(k KEM) KEM {
+	k = registered(k)
+	if p, ok := k.(*provingScheme); ok {
+		return p.base
+	}
+	return k
+}
@@ -0,0 +1,118 @@
+package zkpop
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Object identifiers for the round-3 schemes in this package live under an
+// experimental arc until official identifiers are assigned. Plain KEMs use
+// oidKEM.<n>, their NIZKPoP variants oidNIZKPoP.<n>, with the same leaf n.
+// oidArc.3 holds attributes, such as the CSR attribute of package csr.
+// ML-KEM uses the identifiers assigned by NIST.
+var (
+	oidArc     = asn1.ObjectIdentifier{1, 3, 9999, 77}
+	oidKEM     = append(oidArc[:len(oidArc):len(oidArc)], 1)
+	oidNIZKPoP = append(oidArc[:len(oidArc):len(oidArc)], 2)
+
+	oidMLKEM768  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}
+	oidMLKEM1024 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3}
+)
+
+func kemOID(n int) asn1.ObjectIdentifier {
+	return append(oidKEM[:len(oidKEM):len(oidKEM)], n)
+}
+
+func nizkpopOID(n int) asn1.ObjectIdentifier {
+	return append(oidNIZKPoP[:len(oidNIZKPoP):len(oidNIZKPoP)], n)
+}
+
+// ErrUnknownAlgorithm is returned by Lookup and LookupOID for schemes that
+// are not part of this build.
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+type algorithm struct {
+	kem KEM
+	oid asn1.ObjectIdentifier
+}
+
+// algorithms holds every scheme compiled into this build, in registration
+// order.
+var algorithms []algorithm
+
+func init() {
+	register(Kyber512, kemOID(1))
+	register(Kyber768, kemOID(2))
+	register(Kyber1024, kemOID(3))
+	register(Kyber512_90s, kemOID(4))
+	register(Kyber768_90s, kemOID(5))
+	register(Kyber1024_90s, kemOID(6))
+	register(Frodo640, kemOID(11))
+	register(Frodo976, kemOID(12))
+	register(Frodo1344, kemOID(13))
+	register(Frodo640AES, kemOID(14))
+	register(Frodo640SHAKE, kemOID(15))
+	register(MLKEM768, oidMLKEM768)
+	register(MLKEM1024, oidMLKEM1024)
+
+	register(Kyber512NIZKPoP, nizkpopOID(1))
+	register(Kyber768NIZKPoP, nizkpopOID(2))
+	register(Kyber1024NIZKPoP, nizkpopOID(3))
+	register(Frodo640NIZKPoP, nizkpopOID(11))
+	register(Frodo976NIZKPoP, nizkpopOID(12))
+	register(Frodo1344NIZKPoP, nizkpopOID(13))
+	register(Frodo640AESNIZKPoP, nizkpopOID(14))
+	register(Frodo640SHAKENIZKPoP, nizkpopOID(15))
+}
+
+func register(k KEM, oid asn1.ObjectIdentifier) {
+	for _, a := range algorithms {
+		if strings.EqualFold(a.kem.Name(), k.Name()) || a.oid.Equal(oid) {
+			panic("zkpop: duplicate registration of " + k.Name())
+		}
+	}
+	algorithms = append(algorithms, algorithm{k, oid})
+}
+
+// Lookup returns the scheme with the given name, such as "Kyber768" or
+// "FrodoKEM-640-NIZKPoP". Names are matched case-insensitively. Schemes
+// supporting proofs of possession also implement ProvingKEM.
+func Lookup(name string) (KEM, error) {
+	for _, a := range algorithms {
+		if strings.EqualFold(a.kem.Name(), name) {
+			return a.kem, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+}
+
+// LookupOID returns the scheme identified by oid.
+func LookupOID(oid asn1.ObjectIdentifier) (KEM, error) {
+	for _, a := range algorithms {
+		if a.oid.Equal(oid) {
+			return a.kem, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: OID %s", ErrUnknownAlgorithm, oid)
+}
+
+// OID returns the object identifier assigned to k.
+func OID(k KEM) (asn1.ObjectIdentifier, error) {
+	for _, a := range algorithms {
+		if a.kem == registered(k) {
+			return a.oid, nil
+		}
+	}
+	return nil, fmt.Errorf("no OID assigned to %s", k.Name())
+}
+
+// All returns every scheme supported by this build, in a stable order.
+func All() []KEM {
+	kems := make([]KEM, len(algorithms))
+	for i, a := range algorithms {
+		kems[i] = a.kem
+	}
+	return kems
+}
@@ -0,0 +1,27 @@
+/********************************************************************************************
+* randombytes for the KEM-NIZKPoP libraries.
+*
+* kyber-zkpop and frodo-zkpop draw all of their randomness from an external
+* randombytes. This definition replaces the ones from their test harnesses
+* (and the copy inside libfrodo.a, which the linker no longer needs) and
+* forwards every call to Go, where rng.go decides which source it reads.
+*
+* The source is a handle kept per OS thread: a C call made from Go runs on
+* the thread of the calling goroutine, and withRand locks that goroutine to
+* its thread while the handle is set, so concurrent calls never share one.
+*********************************************************************************************/
+
+#include <stdint.h>
+#include "_cgo_export.h"
+
+static __thread uintptr_t zkpop_rand;
+
+void zkpop_set_rand(uintptr_t h) {
+  zkpop_rand = h;
+}
+
+/* Kyber declares randombytes as returning void, FrodoKEM as returning int;
+   the int version serves both. */
+int randombytes(unsigned char *out, unsigned long long outlen) {
+  return zkpopRandomBytes(zkpop_rand, out, outlen);
+}
@@ -0,0 +1,124 @@
+package zkpop
+
+/*
+#include <stdint.h>
+void zkpop_set_rand(uintptr_t h);
+*/
+import "C"
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
+// WithRand returns a copy of k that reads the randomness for key generation,
+// proof generation and encapsulation from r instead of crypto/rand.Reader.
+// If k is a ProvingKEM, so is the copy. Keys, proofs and OIDs treat the
+// copy as k itself.
+//
+// r applies to the calls made through the copy and nothing else, so copies
+// with different sources can be used side by side. Reads are serialized, so
+// r need not be safe for concurrent use, but concurrent calls through one
+// copy interleave their reads in no particular order. If r returns an
+// error, the call fails with it.
+//
+// ML-KEM generates keys from a 64-byte seed read from r. Its encapsulation
+// always uses crypto/rand, because crypto/mlkem does not take a source.
+func WithRand(k KEM, r io.Reader) KEM {
+	r = &lockedReader{r: r}
+	switch k := k.(type) {
+	case *provingScheme:
+		p := *k
+		p.kemScheme = k.kemScheme.withSource(r, k)
+		return &p
+	case *kemScheme:
+		return k.withSource(r, k)
+	}
+	panic(fmt.Sprintf("zkpop: WithRand: unsupported KEM %T", k))
+}
+
+func (s *kemScheme) withSource(r io.Reader, orig KEM) *kemScheme {
+	k := *s
+	k.rand = r
+	k.orig = registered(orig)
+	return &k
+}
+
+// registered returns the scheme a WithRand copy was made from, or k.
+func registered(k KEM) KEM {
+	switch s := k.(type) {
+	case *kemScheme:
+		if s.orig != nil {
+			return s.orig
+		}
+	case *provingScheme:
+		if s.orig != nil {
+			return s.orig
+		}
+	}
+	return k
+}
+
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Read(p)
+}
+
+// randSource is the io.Reader a C call draws from, with the first error it
+// returned.
+type randSource struct {
+	r   io.Reader
+	err error
+}
+
+// withRand runs f with r as the source of randombytes for every C call f
+// makes on the calling goroutine. Outside withRand, randombytes reads from
+// crypto/rand.Reader.
+//
+// If r fails, the C code sees zeros for that and every later read, and
+// withRand returns the read error instead of the result of f.
+func withRand(r io.Reader, f func() error) error {
+	src := &randSource{r: r}
+	h := cgo.NewHandle(src)
+	defer h.Delete()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.zkpop_set_rand(C.uintptr_t(h))
+	defer C.zkpop_set_rand(0)
+
+	err := f()
+	if src.err != nil {
+		return fmt.Errorf("failed to read randomness: %w", src.err)
+	}
+	return err
+}
+
+//export zkpopRandomBytes
+func zkpopRandomBytes(h C.uintptr_t, out *C.uchar, n C.ulonglong) C.int {
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(out)), int(n))
+	if h == 0 {
+		rand.Read(buf)
+		return 0
+	}
+	src := cgo.Handle(h).Value().(*randSource)
+	if src.err == nil {
+		_, src.err = io.ReadFull(src.r, buf)
+	}
+	if src.err != nil {
+		clear(buf)
+		return -1
+	}
+	return 0
+}
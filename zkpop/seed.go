@@ -0,0 +1,172 @@
+// Deterministic key generation.
+//
+// The FromSeed functions run key generation with the C randombytes fed from
+// a seed instead of the system RNG. All randomness of kyber-zkpop and
+// frodo-zkpop, including the prover's, comes from randombytes, so the same
+// seed always gives byte-identical keys and proofs. Seed sizes:
+//
+//	Kyber, Kyber-90s, FrodoKEM and their NIZKPoP variants   48 bytes (SeedSize)
+//	ML-KEM-768 and ML-KEM-1024                               64 bytes (FIPS 203 d || z)
+//
+// A 48-byte seed initialises the AES256_CTR_DRBG of the NIST rng.c, with no
+// personalization, just as PQCgenKAT_kem does before each key pair. With the
+// seed of a KAT entry, the KEM functions return the pk and sk of that entry.
+//
+// The seed is the private key in another form: store it accordingly.
+package zkpop
+
+import "zkpop-go/zkpop/internal/drbg"
+
+// SeedSize is the size of the seed for the schemes bound from C.
+const SeedSize = drbg.SeedSize
+
+func keyPairFromSeed(scheme string, seed []byte, keyPair func() ([]byte, []byte, error)) ([]byte, []byte, error) {
+	if err := checkSize(ErrInvalidSeedSize, scheme, seed, SeedSize); err != nil {
+		return nil, nil, err
+	}
+	r, err := drbg.New(seed, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pk, sk []byte
+	err = withRand(r, func() error {
+		var err error
+		pk, sk, err = keyPair()
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, sk, nil
+}
+
+func keyPairWithProofFromSeed(scheme string, seed []byte, keyPair func() ([]byte, []byte, []byte, error)) ([]byte, []byte, []byte, error) {
+	if err := checkSize(ErrInvalidSeedSize, scheme, seed, SeedSize); err != nil {
+		return nil, nil, nil, err
+	}
+	r, err := drbg.New(seed, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var pk, sk, proof []byte
+	err = withRand(r, func() error {
+		var err error
+		pk, sk, proof, err = keyPair()
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pk, sk, proof, nil
+}
+
+// KeyPairKyber512FromSeed derives a Kyber512 key pair from a 48-byte seed.
+func KeyPairKyber512FromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("Kyber512", seed, KeyPairKyber512)
+}
+
+// KeyPairKyber768FromSeed derives a Kyber768 key pair from a 48-byte seed.
+func KeyPairKyber768FromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("Kyber768", seed, KeyPairKyber768)
+}
+
+// KeyPairKyber1024FromSeed derives a Kyber1024 key pair from a 48-byte seed.
+func KeyPairKyber1024FromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("Kyber1024", seed, KeyPairKyber1024)
+}
+
+// KeyPairKyber512_90sFromSeed derives a Kyber512-90s key pair from a 48-byte
+// seed.
+func KeyPairKyber512_90sFromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("Kyber512-90s", seed, KeyPairKyber512_90s)
+}
+
+// KeyPairKyber768_90sFromSeed derives a Kyber768-90s key pair from a 48-byte
+// seed.
+func KeyPairKyber768_90sFromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("Kyber768-90s", seed, KeyPairKyber768_90s)
+}
+
+// KeyPairKyber1024_90sFromSeed derives a Kyber1024-90s key pair from a
+// 48-byte seed.
+func KeyPairKyber1024_90sFromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("Kyber1024-90s", seed, KeyPairKyber1024_90s)
+}
+
+// KeyPairFrodo640FromSeed derives a FrodoKEM-640 key pair from a 48-byte
+// seed.
+func KeyPairFrodo640FromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("FrodoKEM-640", seed, KeyPairFrodo640)
+}
+
+// KeyPairFrodo976FromSeed derives a FrodoKEM-976 key pair from a 48-byte
+// seed.
+func KeyPairFrodo976FromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("FrodoKEM-976", seed, KeyPairFrodo976)
+}
+
+// KeyPairFrodo1344FromSeed derives a FrodoKEM-1344 key pair from a 48-byte
+// seed.
+func KeyPairFrodo1344FromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("FrodoKEM-1344", seed, KeyPairFrodo1344)
+}
+
+// KeyPairFrodo640AESFromSeed derives a FrodoKEM-640-AES key pair from a
+// 48-byte seed.
+func KeyPairFrodo640AESFromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("FrodoKEM-640-AES", seed, KeyPairFrodo640AES)
+}
+
+// KeyPairFrodo640SHAKEFromSeed derives a FrodoKEM-640-SHAKE key pair from a
+// 48-byte seed.
+func KeyPairFrodo640SHAKEFromSeed(seed []byte) ([]byte, []byte, error) {
+	return keyPairFromSeed("FrodoKEM-640-SHAKE", seed, KeyPairFrodo640SHAKE)
+}
+
+// KeyPairKyber512NIZKPoPFromSeed derives a Kyber512 key pair and its NIZKPoP
+// from a 48-byte seed.
+func KeyPairKyber512NIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("Kyber512-NIZKPoP", seed, KeyPairKyber512NIZKPoP)
+}
+
+// KeyPairKyber768NIZKPoPFromSeed derives a Kyber768 key pair and its NIZKPoP
+// from a 48-byte seed.
+func KeyPairKyber768NIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("Kyber768-NIZKPoP", seed, KeyPairKyber768NIZKPoP)
+}
+
+// KeyPairKyber1024NIZKPoPFromSeed derives a Kyber1024 key pair and its
+// NIZKPoP from a 48-byte seed.
+func KeyPairKyber1024NIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("Kyber1024-NIZKPoP", seed, KeyPairKyber1024NIZKPoP)
+}
+
+// KeyPairFrodo640NIZKPoPFromSeed derives a FrodoKEM-640 key pair and its
+// NIZKPoP from a 48-byte seed.
+func KeyPairFrodo640NIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("FrodoKEM-640-NIZKPoP", seed, KeyPairFrodo640NIZKPoP)
+}
+
+// KeyPairFrodo976NIZKPoPFromSeed derives a FrodoKEM-976 key pair and its
+// NIZKPoP from a 48-byte seed.
+func KeyPairFrodo976NIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("FrodoKEM-976-NIZKPoP", seed, KeyPairFrodo976NIZKPoP)
+}
+
+// KeyPairFrodo1344NIZKPoPFromSeed derives a FrodoKEM-1344 key pair and its
+// NIZKPoP from a 48-byte seed.
+func KeyPairFrodo1344NIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("FrodoKEM-1344-NIZKPoP", seed, KeyPairFrodo1344NIZKPoP)
+}
+
+// KeyPairFrodo640AESNIZKPoPFromSeed derives a FrodoKEM-640-AES key pair and
+// its NIZKPoP from a 48-byte seed.
+func KeyPairFrodo640AESNIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("FrodoKEM-640-AES-NIZKPoP", seed, KeyPairFrodo640AESNIZKPoP)
+}
+
+// KeyPairFrodo640SHAKENIZKPoPFromSeed derives a FrodoKEM-640-SHAKE key pair
+// and its NIZKPoP from a 48-byte seed.
+func KeyPairFrodo640SHAKENIZKPoPFromSeed(seed []byte) ([]byte, []byte, []byte, error) {
+	return keyPairWithProofFromSeed("FrodoKEM-640-SHAKE-NIZKPoP", seed, KeyPairFrodo640SHAKENIZKPoP)
+}
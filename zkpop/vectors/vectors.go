@@ -0,0 +1,151 @@
+// Package vectors produces and checks golden NIZKPoP test vectors.
+//
+// A Set holds, for one scheme, honest (pk, proof) pairs derived from fixed
+// seeds with GenerateKeyPairWithProofFromSeed, and corrupted variants of
+// them that every verifier must reject:
+//
+//	commitment-N   one bit flipped in the N-th 32-byte block at the start of
+//	               the proof, where the commitment hashes and the
+//	               Fiat-Shamir challenge are
+//	audit-parties  the openings after the first block rotated by 32 bytes,
+//	               so each opened value sits where the verifier expects
+//	               another party's
+//	truncated-*    the open bundle cut short: by one byte, to half its
+//	               length, and to the first block only
+//	other-pk       a valid proof presented with the public key of the next
+//	               vector
+//
+// Since the same seed gives byte-identical keys and proofs, Check also
+// regenerates each honest vector and compares it with the stored one.
+package vectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"zkpop-go/zkpop"
+	"zkpop-go/zkpop/kat"
+)
+
+// blockSize is the granularity of the mutations. Every proof starts with at
+// least one hash of this size.
+const blockSize = 32
+
+// Set is the JSON form of the vectors of one scheme.
+type Set struct {
+	Scheme  string     `json:"scheme"`
+	Valid   []Vector   `json:"valid"`
+	Invalid []Mutation `json:"invalid"`
+}
+
+// Vector is an honest key and proof.
+type Vector struct {
+	Seed  Hex    `json:"seed"`
+	PK    []byte `json:"pk"`
+	Proof []byte `json:"proof"`
+}
+
+// Mutation is a corrupted vector. Of is the index of the Vector it was
+// derived from.
+type Mutation struct {
+	Name  string `json:"name"`
+	Of    int    `json:"of"`
+	PK    []byte `json:"pk"`
+	Proof []byte `json:"proof"`
+}
+
+// Hex is a byte string encoded as hexadecimal in JSON.
+type Hex []byte
+
+func (h Hex) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(h)), nil
+}
+
+func (h *Hex) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	*h = b
+	return err
+}
+
+// Generate derives n honest vectors for p from the first n KAT seeds and
+// adds their mutations. n must be at least 2, so that other-pk has another
+// key to use.
+func Generate(p zkpop.ProvingKEM, n int) (*Set, error) {
+	if n < 2 {
+		return nil, errors.New("vectors: need at least 2 vectors")
+	}
+	s := &Set{Scheme: p.Name()}
+	for _, seed := range kat.Seeds(n) {
+		pk, _, proof, err := p.GenerateKeyPairWithProofFromSeed(seed)
+		if err != nil {
+			return nil, err
+		}
+		s.Valid = append(s.Valid, Vector{Seed: seed, PK: pk, Proof: proof})
+	}
+	for i, v := range s.Valid {
+		other := s.Valid[(i+1)%len(s.Valid)].PK
+		s.Invalid = append(s.Invalid, mutate(i, v, other)...)
+	}
+	return s, nil
+}
+
+func mutate(i int, v Vector, otherPK []byte) []Mutation {
+	var ms []Mutation
+	add := func(name string, pk, proof []byte) {
+		ms = append(ms, Mutation{Name: name, Of: i, PK: pk, Proof: proof})
+	}
+	for b := 0; b < 3 && b*blockSize < len(v.Proof); b++ {
+		proof := bytes.Clone(v.Proof)
+		proof[b*blockSize] ^= 1
+		add(fmt.Sprintf("commitment-%d", b), v.PK, proof)
+	}
+	if body := v.Proof[min(blockSize, len(v.Proof)):]; len(body) > blockSize {
+		proof := append(bytes.Clone(v.Proof[:blockSize]), body[blockSize:]...)
+		proof = append(proof, body[:blockSize]...)
+		add("audit-parties", v.PK, proof)
+	}
+	add("truncated-1", v.PK, bytes.Clone(v.Proof[:len(v.Proof)-1]))
+	add("truncated-half", v.PK, bytes.Clone(v.Proof[:len(v.Proof)/2]))
+	if len(v.Proof) > blockSize {
+		add("truncated-open-bundle", v.PK, bytes.Clone(v.Proof[:blockSize]))
+	}
+	if !bytes.Equal(v.PK, otherPK) {
+		add("other-pk", otherPK, v.Proof)
+	}
+	return ms
+}
+
+// Check verifies the set against p: every valid vector must be reproduced
+// from its seed and accepted, and every mutation rejected with a
+// *zkpop.VerifyError. It returns one error per failed vector.
+func Check(p zkpop.ProvingKEM, s *Set) []error {
+	var errs []error
+	if s.Scheme != p.Name() {
+		return []error{fmt.Errorf("vectors are for %s, not %s", s.Scheme, p.Name())}
+	}
+	for i, v := range s.Valid {
+		pk, _, proof, err := p.GenerateKeyPairWithProofFromSeed(v.Seed)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("valid %d: %w", i, err))
+		case !bytes.Equal(pk, v.PK) || !bytes.Equal(proof, v.Proof):
+			errs = append(errs, fmt.Errorf("valid %d: seed does not reproduce the stored key and proof", i))
+		}
+		if err := p.VerifyProof(v.PK, v.Proof); err != nil {
+			errs = append(errs, fmt.Errorf("valid %d: %w", i, err))
+		}
+	}
+	for _, m := range s.Invalid {
+		err := p.VerifyProof(m.PK, m.Proof)
+		var ve *zkpop.VerifyError
+		switch {
+		case err == nil:
+			errs = append(errs, fmt.Errorf("invalid %s of %d: accepted", m.Name, m.Of))
+		case !errors.As(err, &ve):
+			errs = append(errs, fmt.Errorf("invalid %s of %d: %w", m.Name, m.Of, err))
+		}
+	}
+	return errs
+}
@@ -17,6 +17,9 @@ import (
 
 // Frodo640-Keypair with NIZKPoP
 func KeyPairFrodo640NIZKPoP() ([]byte, []byte, []byte, error) {
+	if err := checkCPU(); err != nil {
+		return nil, nil, nil, err
+	}
 	pk := make([]byte, C.CRYPTO_PUBLICKEYBYTES)
 	sk := make([]byte, C.CRYPTO_SECRETKEYBYTES)
 	var zkpop_c *C.uint8_t
@@ -38,10 +41,28 @@ func KeyPairFrodo640NIZKPoP() ([]byte, []byte, []byte, error) {
 	return pk, sk, zkpopGo, nil
 }
 
+// frodo640ProofLimits bounds FrodoKEM-640 proofs. The frodo-zkpop headers
+// do not export a maximum proof size.
+var frodo640ProofLimits = proofLimits{
+	pkSize: C.CRYPTO_PUBLICKEYBYTES,
+	min:    1,
+}
+
+// VerifyFrodo640NIZKPoP checks a FrodoKEM-640 NIZKPoP and returns a
+// *VerifyError explaining why it was rejected, or nil if it is valid.
+func VerifyFrodo640NIZKPoP(pk []byte, zkpop []byte) error {
+	return verifyProof(C.CRYPTO_ALGNAME+"-NIZKPoP", frodo640ProofLimits, pk, zkpop, func() int {
+		return int(C.crypto_nizkpop_verify_Frodo640(
+			(*C.uchar)(unsafe.Pointer(&pk[0])),
+			(*C.uchar)(unsafe.Pointer(&zkpop[0])),
+			C.ulong(len(zkpop))))
+	})
+}
+
+// VerifyFrodo640ZKPop reports whether zkpop is a valid NIZKPoP for pk.
 func VerifyFrodo640ZKPop(pk []byte, zkpop []byte) bool {
-	ret := C.crypto_nizkpop_verify_Frodo640(
-		(*C.uchar)(unsafe.Pointer(&pk[0])),
-		(*C.uchar)(unsafe.Pointer(&zkpop[0])),
-		C.ulong(len(zkpop)))
-	return ret == 0
-}
\ No newline at end of file
+	return VerifyFrodo640NIZKPoP(pk, zkpop) == nil
+}
+
+// Frodo640NIZKPoP is FrodoKEM-640 with key generation producing a NIZKPoP.
+var Frodo640NIZKPoP ProvingKEM = frodo640KEM.withProof(C.CRYPTO_ALGNAME+"-NIZKPoP", frodoDefaultProfile, frodo640ProofLimits, KeyPairFrodo640NIZKPoP, VerifyFrodo640NIZKPoP)